@@ -1,5 +1,7 @@
 package errx
 
+import "sort"
+
 func NewBuilder(namespace string, args ...SetOptionFn) *Builder {
 	b := &Builder{
 		errMap:    make(map[string]*Error),
@@ -8,6 +10,9 @@ func NewBuilder(namespace string, args ...SetOptionFn) *Builder {
 
 	// Evaluate options
 	o := evaluateOptions(args)
+	b.exitCode = o.exitCode
+	b.registry = o.registry
+	b.conf = newLocalConfig(o)
 
 	// Set fallback error and override namespace
 	if o.fallbackErr != nil {
@@ -16,6 +21,12 @@ func NewBuilder(namespace string, args ...SetOptionFn) *Builder {
 		b.fallbackErr = InternalError().Copy(WithNamespace(b.namespace))
 	}
 
+	// Set default exit code to fallback error
+	if b.fallbackErr.exitCode == 0 {
+		b.fallbackErr.exitCode = b.exitCode
+	}
+	b.fallbackErr.conf = b.conf
+
 	return b
 }
 
@@ -24,17 +35,22 @@ type Builder struct {
 	errMap      map[string]*Error
 	namespace   string
 	fallbackErr *Error
+	exitCode    int
+	registry    *Registry
+	conf        *config
 }
 
 // NewError create new error and ensure error is unique by it's code
 func (b *Builder) NewError(code string, message string, args ...SetOptionFn) *Error {
 	// Create error
 	args = b.mergeArgs(args)
-	err := NewError(code, message, args...)
+	err := newError(code, message, args...)
 
 	// Register error to dictionary, always overwrite existing
 	b.registerError(err)
 
+	newHooks.fire(err, 1)
+
 	return err
 }
 
@@ -52,11 +68,64 @@ func (b *Builder) Namespace() string {
 	return b.namespace
 }
 
+// Codes returns sorted error codes registered in builder, including fallback error code
+func (b *Builder) Codes() []string {
+	codes := make([]string, 0, len(b.errMap)+1)
+	codes = append(codes, b.fallbackErr.Code())
+	for code := range b.errMap {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// ExitCode is getter function to retrieve default exit code of errors registered in builder
+func (b *Builder) ExitCode() int {
+	return b.exitCode
+}
+
 // FallbackError is getter function to retrieve FallbackError value
 func (b *Builder) FallbackError() *Error {
 	return b.fallbackErr
 }
 
+// Registry is getter function to retrieve translator registry used by builder
+func (b *Builder) Registry() *Registry {
+	if b.registry == nil {
+		return DefaultRegistry
+	}
+	return b.registry
+}
+
+// Trace wrap and trace error. If error is not *errx.Error then it will be translated by builder registry,
+// or wrapped into builder fallback error if no translator handles it
+func (b *Builder) Trace(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	tErr := translate(b.Registry(), err, b.FallbackError)
+
+	return tErr.Trace(Source(err), SkipTrace(2))
+}
+
+// Wrap set err as source of error translated by builder registry, or builder fallback error
+// if no translator handles it
+func (b *Builder) Wrap(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	tErr, ok := b.Registry().Translate(err)
+	if !ok {
+		tErr = b.fallbackErr
+	}
+
+	nErr := tErr.wrap(err)
+	wrapHooks.fire(nErr, 1)
+	return nErr
+}
+
 // CopyError take error input and override the namespace
 func (b *Builder) CopyError(err *Error, args ...SetOptionFn) *Error {
 	// Copy error and override namespace
@@ -82,5 +151,11 @@ func (b *Builder) registerError(err *Error) {
 		panic(DuplicateFallbackError)
 	}
 
+	// Set default exit code if error does not have one
+	if err.exitCode == 0 {
+		err.exitCode = b.exitCode
+	}
+	err.conf = b.conf
+
 	b.errMap[err.Code()] = err
 }
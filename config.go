@@ -0,0 +1,238 @@
+package errx
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// globalConfig holds *config set by Configure
+var globalConfig atomic.Value
+
+// Configure replaces global configuration of how traces are recorded and printed. Options that are not related to
+// configuration are ignored. Configure should be called on program start, before any error is traced
+func Configure(args ...SetOptionFn) {
+	o := evaluateOptions(args)
+	globalConfig.Store(&config{
+		recordTime:      o.recordTime,
+		recordGoroutine: o.recordGoroutine,
+		showElapsed:     o.showElapsed,
+		pathFormatter:   o.pathFormatter,
+		frames:          o.frames,
+		sourceLines:     o.sourceLines,
+		formatter:       o.formatter,
+	})
+}
+
+// TracePath set how file path is printed in Traces and Error output, e.g. TracePath(ModuleRelative).
+// It can be set globally with Configure, or per Builder which overrides global configuration
+func TracePath(f PathFormatter) SetOptionFn {
+	return func(o *options) {
+		o.pathFormatter = f
+	}
+}
+
+// RecordTime records timestamp on each trace entry. Use Error.Elapsed to retrieve time elapsed between traces
+func RecordTime() SetOptionFn {
+	return func(o *options) {
+		o.recordTime = true
+	}
+}
+
+// RecordGoroutine records ID of goroutine that traced the error on each trace entry
+func RecordGoroutine() SetOptionFn {
+	return func(o *options) {
+		o.recordGoroutine = true
+	}
+}
+
+// ShowElapsed prints time elapsed since previous trace and goroutine ID, if recorded, next to each trace
+// in Error() output, e.g. "/app/service.go:42 +12ms [goroutine 7]"
+func ShowElapsed() SetOptionFn {
+	return func(o *options) {
+		o.showElapsed = true
+	}
+}
+
+type config struct {
+	recordTime      bool
+	recordGoroutine bool
+	showElapsed     bool
+	pathFormatter   PathFormatter
+	frames          *frameRules
+	sourceLines     *int
+	formatter       Formatter
+}
+
+// newLocalConfig returns configuration that can be overridden per Builder or per call, or nil if nothing is set
+func newLocalConfig(o *options) *config {
+	if o.pathFormatter == nil && o.frames == nil && o.sourceLines == nil && o.formatter == nil {
+		return nil
+	}
+	return &config{
+		pathFormatter: o.pathFormatter,
+		frames:        o.frames,
+		sourceLines:   o.sourceLines,
+		formatter:     o.formatter,
+	}
+}
+
+// override returns copy of configuration with values that are set in other configuration
+func (c *config) override(other *config) *config {
+	if other == nil {
+		return c
+	}
+
+	merged := *c
+	if other.pathFormatter != nil {
+		merged.pathFormatter = other.pathFormatter
+	}
+	if other.frames != nil {
+		merged.frames = other.frames
+	}
+	if other.sourceLines != nil {
+		merged.sourceLines = other.sourceLines
+	}
+	if other.formatter != nil {
+		merged.formatter = other.formatter
+	}
+	return &merged
+}
+
+// config resolve configuration of error. Builder configuration overrides global configuration,
+// and options set on call overrides both
+func (e *Error) config(args ...SetOptionFn) *config {
+	c := loadConfig().override(e.conf)
+	if len(args) > 0 {
+		c = c.override(newLocalConfig(evaluateOptions(args)))
+	}
+	return c
+}
+
+// traceLines formats traces, one trace per line, with frames filtered by configured frame rules.
+// If detail is true, then elapsed time, goroutine ID and note are printed next to trace location
+func (c *config) traceLines(traces []TraceEntry, detail bool) []string {
+	keep := make([]bool, len(traces))
+	for i, t := range traces {
+		keep[i] = c.frames.keep(t)
+	}
+
+	sourceLines := 0
+	if detail {
+		sourceLines = c.showSource()
+	}
+
+	lines := make([]string, 0, len(traces))
+	omitted := 0
+	for i, t := range traces {
+		if !keep[i] {
+			omitted++
+			continue
+		}
+
+		if omitted > 0 && c.frames.collapse {
+			lines = append(lines, omittedFrames(omitted))
+		}
+		omitted = 0
+
+		line := c.location(t)
+		if detail {
+			// Find previous trace that is printed
+			prev := i + 1
+			for prev < len(traces) && !keep[prev] {
+				prev++
+			}
+			if prev < len(traces) && c.showElapsed && !t.Time.IsZero() && !traces[prev].Time.IsZero() {
+				line += " " + formatElapsed(t.Time.Sub(traces[prev].Time))
+			}
+			if t.Goroutine != 0 && c.showElapsed {
+				line += fmt.Sprintf(" [goroutine %d]", t.Goroutine)
+			}
+			if t.Note != "" {
+				line += " (" + t.Note + ")"
+			}
+		}
+		lines = append(lines, line)
+
+		if sourceLines > 0 {
+			lines = append(lines, sourceSnippet(t, sourceLines)...)
+		}
+	}
+
+	if omitted > 0 && c.frames.collapse {
+		lines = append(lines, omittedFrames(omitted))
+	}
+
+	return lines
+}
+
+// enclosedTraceLines formats traces in detail, with frames that are shared with enclosing traces printed
+// as "... N more"
+func (c *config) enclosedTraceLines(traces, enclosing []TraceEntry) []string {
+	common := commonFrames(traces, enclosing)
+	lines := c.traceLines(traces[:len(traces)-common], true)
+	if common > 0 {
+		lines = append(lines, "... "+strconv.Itoa(common)+" more")
+	}
+	return lines
+}
+
+// showSource returns number of source lines printed around traces. If not configured, SourceEnv is used
+func (c *config) showSource() int {
+	if c.sourceLines == nil {
+		return sourceLinesFromEnv()
+	}
+	return *c.sourceLines
+}
+
+// location returns trace location in "file:line" format with file path formatted by configured path formatter
+func (c *config) location(t TraceEntry) string {
+	if c.pathFormatter == nil {
+		return t.String()
+	}
+	return c.pathFormatter(t) + ":" + strconv.Itoa(t.Line)
+}
+
+func loadConfig() *config {
+	if c, ok := globalConfig.Load().(*config); ok {
+		return c
+	}
+	return &config{}
+}
+
+// stamp set timestamp and goroutine ID to trace entries if enabled by configuration
+func stamp(c *config, entries ...*TraceEntry) {
+	if !c.recordTime && !c.recordGoroutine {
+		return
+	}
+
+	var now time.Time
+	if c.recordTime {
+		now = time.Now()
+	}
+
+	var gid int64
+	if c.recordGoroutine {
+		gid = goroutineID()
+	}
+
+	for _, t := range entries {
+		t.Time = now
+		t.Goroutine = gid
+	}
+}
+
+// goroutineID parse current goroutine ID from stack header, e.g. "goroutine 7 [running]:"
+func goroutineID() int64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i >= 0 {
+		buf = buf[:i]
+	}
+	id, _ := strconv.ParseInt(string(buf), 10, 64)
+	return id
+}
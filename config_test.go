@@ -0,0 +1,145 @@
+package errx_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/nbs-go/errx"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testConfig prints traces in package path, so trace assertions do not depend on where repository is checked out
+var testConfig = []errx.SetOptionFn{errx.TracePath(errx.PackagePath)}
+
+func TestMain(m *testing.M) {
+	errx.Configure(testConfig...)
+	os.Exit(m.Run())
+}
+
+func TestRecordTime(t *testing.T) {
+	errx.Configure(append(testConfig, errx.RecordTime(), errx.RecordGoroutine(), errx.ShowElapsed())...)
+	defer errx.Configure(testConfig...)
+
+	err := errx.Trace(errors.New("timeout"))
+	time.Sleep(20 * time.Millisecond)
+	err = err.(*errx.Error).Trace(errx.WithNote("while loading invoice"))
+
+	xErr := err.(*errx.Error)
+	entries := xErr.TraceEntries()
+	if len(entries) != 2 || entries[0].Time.IsZero() || entries[0].Goroutine == 0 {
+		t.Errorf("unexpected trace entries. Entries = %+v", entries)
+		return
+	}
+
+	elapsed := xErr.Elapsed()
+	if len(elapsed) != 1 || elapsed[0] < 20*time.Millisecond {
+		t.Errorf("unexpected elapsed. Elapsed = %v", elapsed)
+	}
+
+	pattern := regexp.MustCompile(`config_test\.go:\d+ \+\d+ms \[goroutine \d+] \(while loading invoice\)`)
+	if msg := xErr.Error(); !pattern.MatchString(msg) {
+		t.Errorf("unexpected error message. Message = %s", msg)
+	}
+
+	// Timestamp is kept on serialization
+	b, _ := json.Marshal(xErr)
+	var decoded errx.Error
+	if jErr := json.Unmarshal(b, &decoded); jErr != nil || !decoded.TraceEntries()[0].Equal(entries[0]) {
+		t.Errorf("unexpected decoded trace entries. Entries = %+v", decoded.TraceEntries())
+	}
+}
+
+func TestRecordTimeDisabled(t *testing.T) {
+	err := errx.InternalError().Trace().Trace()
+
+	if entries := err.TraceEntries(); !entries[0].Time.IsZero() || entries[0].Goroutine != 0 {
+		t.Errorf("unexpected recorded time. Entries = %+v", entries)
+	}
+
+	if elapsed := err.Elapsed(); len(elapsed) != 1 || elapsed[0] != 0 {
+		t.Errorf("unexpected elapsed. Elapsed = %v", elapsed)
+	}
+
+	if regexp.MustCompile(`\+\d+`).MatchString(err.Error()) {
+		t.Errorf("unexpected elapsed in error message. Message = %s", err)
+	}
+}
+
+func TestTracePath(t *testing.T) {
+	entry := errx.InternalError().Trace().TraceEntries()[0]
+
+	testCases := []struct {
+		formatter errx.PathFormatter
+		expected  string
+	}{
+		{errx.FullPath, entry.File},
+		{errx.BaseName, "config_test.go"},
+		{errx.PackagePath, "github.com/nbs-go/errx/config_test.go"},
+		{errx.ModuleRelative, "config_test.go"},
+		{func(t errx.TraceEntry) string { return strings.ToUpper(errx.BaseName(t)) }, "CONFIG_TEST.GO"},
+	}
+
+	for i, tc := range testCases {
+		if actual := tc.formatter(entry); actual != tc.expected {
+			t.Errorf("unexpected formatted path. Index = %d, Path = %s", i, actual)
+		}
+	}
+
+	// Dot in package directory is escaped in function name
+	entry = errx.TraceEntry{
+		File:     "/go/pkg/mod/gopkg.in/yaml.v3@v3.0.1/decode.go",
+		Line:     10,
+		Function: "gopkg.in/yaml%2ev3.(*parser).parse",
+	}
+	if actual := errx.PackagePath(entry); actual != "gopkg.in/yaml.v3/decode.go" {
+		t.Errorf("unexpected package path. Path = %s", actual)
+	}
+
+	if actual := errx.ModuleRelative(entry); actual != "gopkg.in/yaml.v3/decode.go" {
+		t.Errorf("unexpected module relative path. Path = %s", actual)
+	}
+
+	var err errx.Error
+	b, _ := json.Marshal(map[string]interface{}{"code": "ERR_1", "traces": []errx.TraceEntry{entry}})
+	if jErr := json.Unmarshal(b, &err); jErr != nil {
+		t.Errorf("unexpected error on decoding error. Error = %s", jErr)
+		return
+	}
+	if traces := err.Traces(errx.DropPackages("gopkg.in/yaml.v3")); len(traces) != 0 {
+		t.Errorf("unexpected traces of dotted package are not dropped. Traces = %v", traces)
+	}
+
+	// Unknown function fallback to full path
+	entry.Function = ""
+	if actual := errx.ModuleRelative(entry); actual != entry.File {
+		t.Errorf("unexpected path of unknown function. Path = %s", actual)
+	}
+}
+
+func TestBuilderTracePath(t *testing.T) {
+	b := errx.NewBuilder("myapp", errx.TracePath(errx.BaseName))
+	notFound := b.NewError("E_NOT_FOUND", "Not found")
+
+	err := notFound.Trace()
+	expected := regexp.MustCompile(`^config_test\.go:\d+$`)
+	if traces := err.Traces(); !expected.MatchString(traces[0]) {
+		t.Errorf("unexpected builder trace. Trace = %s", traces[0])
+	}
+
+	if msg := b.Trace(errors.New("timeout")).Error(); !strings.Contains(msg, "Traces => config_test.go:") {
+		t.Errorf("unexpected builder fallback traces. Message = %s", msg)
+	}
+
+	// Error without builder uses global configuration
+	if traces := errx.InternalError().Trace().Traces(); !strings.HasPrefix(traces[0], "github.com/nbs-go/errx/") {
+		t.Errorf("unexpected global trace. Trace = %s", traces[0])
+	}
+
+	// Raw file path is kept in trace entries
+	if file := err.TraceEntries()[0].File; !strings.HasSuffix(file, "/config_test.go") || file == "config_test.go" {
+		t.Errorf("unexpected trace entry file. File = %s", file)
+	}
+}
@@ -6,3 +6,8 @@ const (
 
 var DuplicateFallbackError = NewError("ERR_1", "Cannot create new Error that has same code with Fallback Error",
 	WithNamespace(pkgNamespace))
+
+var GroupFailedError = NewError("ERR_2", "One or more tasks failed", WithNamespace(pkgNamespace))
+
+var MetadataCollisionError = NewError("ERR_3", "Metadata key already exists with different value",
+	WithNamespace(pkgNamespace))
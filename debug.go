@@ -0,0 +1,183 @@
+package errx
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewRecentErrors creates a bounded ring buffer that keeps the last size errors
+func NewRecentErrors(size int) *RecentErrors {
+	if size <= 0 {
+		size = 100
+	}
+	return &RecentErrors{
+		entries: make([]Occurrence, size),
+		stats:   make(map[statKey]*CodeStat),
+	}
+}
+
+// RecentErrors keeps recent errors in a bounded ring buffer and counts occurrences per code.
+// It can be served as a debug page with Handler, e.g. http.Handle("/debug/errx", recent.Handler())
+type RecentErrors struct {
+	mu      sync.Mutex
+	entries []Occurrence
+	next    int
+	size    int
+	stats   map[statKey]*CodeStat
+}
+
+// Occurrence is a recorded error
+type Occurrence struct {
+	Time  time.Time `json:"time"`
+	Error *Error    `json:"error"`
+}
+
+// CodeStat is occurrences summary of an error code
+type CodeStat struct {
+	Namespace string    `json:"namespace"`
+	Code      string    `json:"code"`
+	Count     uint64    `json:"count"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+type statKey struct {
+	namespace string
+	code      string
+}
+
+// Record add error to buffer. If error is not *errx.Error then it will be wrapped into InternalError
+func (r *RecentErrors) Record(err error) {
+	if err == nil {
+		return
+	}
+
+	xErr, ok := err.(*Error)
+	if !ok {
+		xErr = InternalError().wrap(err)
+	}
+
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Write to ring buffer
+	r.entries[r.next] = Occurrence{Time: now, Error: xErr}
+	r.next = (r.next + 1) % len(r.entries)
+	if r.size < len(r.entries) {
+		r.size++
+	}
+
+	// Update stats
+	k := statKey{namespace: xErr.namespace, code: xErr.code}
+	s, ok := r.stats[k]
+	if !ok {
+		s = &CodeStat{Namespace: k.namespace, Code: k.code, FirstSeen: now}
+		r.stats[k] = s
+	}
+	s.Count++
+	s.LastSeen = now
+}
+
+// Occurrences returns recent errors from the newest one. If namespace or code is not empty, then errors are filtered
+func (r *RecentErrors) Occurrences(namespace, code string) []Occurrence {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]Occurrence, 0, r.size)
+	for i := 1; i <= r.size; i++ {
+		o := r.entries[(r.next-i+len(r.entries))%len(r.entries)]
+		if matchFilter(o.Error, namespace, code) {
+			result = append(result, o)
+		}
+	}
+	return result
+}
+
+// Stats returns occurrences summary per code sorted by the last seen. If namespace or code is not empty,
+// then stats are filtered
+func (r *RecentErrors) Stats(namespace, code string) []CodeStat {
+	r.mu.Lock()
+	result := make([]CodeStat, 0, len(r.stats))
+	for k, s := range r.stats {
+		if (namespace == "" || k.namespace == namespace) && (code == "" || k.code == code) {
+			result = append(result, *s)
+		}
+	}
+	r.mu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LastSeen.After(result[j].LastSeen)
+	})
+	return result
+}
+
+func matchFilter(err *Error, namespace, code string) bool {
+	return (namespace == "" || err.namespace == namespace) && (code == "" || err.code == code)
+}
+
+// Handler returns http.Handler that serves recent errors. It serves JSON if query format=json is set or
+// request accepts application/json, else it serves HTML. Errors can be filtered by namespace and code query
+func (r *RecentErrors) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		q := req.URL.Query()
+		data := debugPage{
+			Namespace:   q.Get("namespace"),
+			Code:        q.Get("code"),
+			Stats:       r.Stats(q.Get("namespace"), q.Get("code")),
+			Occurrences: r.Occurrences(q.Get("namespace"), q.Get("code")),
+		}
+
+		if q.Get("format") == "json" || strings.Contains(req.Header.Get("Accept"), "application/json") {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(data)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = debugTemplate.Execute(w, data)
+	})
+}
+
+type debugPage struct {
+	Namespace   string       `json:"namespace,omitempty"`
+	Code        string       `json:"code,omitempty"`
+	Stats       []CodeStat   `json:"stats"`
+	Occurrences []Occurrence `json:"occurrences"`
+}
+
+var debugTemplate = template.Must(template.New("errx").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<title>errx: recent errors</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+pre { background: #f6f6f6; padding: 8px; }
+</style>
+</head>
+<body>
+<h1>Recent errors</h1>
+<form>
+Namespace <input name="namespace" value="{{.Namespace}}">
+Code <input name="code" value="{{.Code}}">
+<button type="submit">Filter</button>
+</form>
+<h2>Counts</h2>
+<table>
+<tr><th>Namespace</th><th>Code</th><th>Count</th><th>First Seen</th><th>Last Seen</th></tr>
+{{range .Stats}}<tr><td>{{.Namespace}}</td><td>{{.Code}}</td><td>{{.Count}}</td><td>{{.FirstSeen.Format "2006-01-02T15:04:05.000Z07:00"}}</td><td>{{.LastSeen.Format "2006-01-02T15:04:05.000Z07:00"}}</td></tr>
+{{end}}</table>
+<h2>Occurrences</h2>
+{{range .Occurrences}}<h3>{{.Time.Format "2006-01-02T15:04:05.000Z07:00"}}</h3>
+<pre>{{.Error.Error}}</pre>
+{{end}}</body>
+</html>
+`))
@@ -0,0 +1,119 @@
+package errx_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/nbs-go/errx"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecentErrorsRingBuffer(t *testing.T) {
+	r := errx.NewRecentErrors(3)
+
+	for i := 1; i <= 5; i++ {
+		r.Record(errx.NewError(fmt.Sprintf("ERR_%d", i), "Failure", errx.WithNamespace("myapp")))
+	}
+	r.Record(nil)
+
+	occurrences := r.Occurrences("", "")
+	if len(occurrences) != 3 {
+		t.Errorf("unexpected occurrences length. Length = %d", len(occurrences))
+		return
+	}
+
+	for i, code := range []string{"ERR_5", "ERR_4", "ERR_3"} {
+		if c := occurrences[i].Error.Code(); c != code {
+			t.Errorf("unexpected occurrence order. Index = %d, Code = %s", i, c)
+		}
+	}
+
+	// Stats are kept even if occurrence is evicted from buffer
+	if stats := r.Stats("", ""); len(stats) != 5 {
+		t.Errorf("unexpected stats length. Length = %d", len(stats))
+	}
+}
+
+func TestRecentErrorsStats(t *testing.T) {
+	r := errx.NewRecentErrors(10)
+
+	notFound := errx.NewError("ERR_1", "Resource not found", errx.WithNamespace("myapp"))
+	r.Record(notFound.Trace())
+	r.Record(notFound.Trace())
+	r.Record(fmt.Errorf("connection reset"))
+
+	stats := r.Stats("myapp", "")
+	if len(stats) != 1 {
+		t.Errorf("unexpected filtered stats length. Length = %d", len(stats))
+		return
+	}
+
+	if s := stats[0]; s.Count != 2 || s.FirstSeen.After(s.LastSeen) {
+		t.Errorf("unexpected stat. Stat = %+v", s)
+	}
+
+	occurrences := r.Occurrences("", "ERROR")
+	if len(occurrences) != 1 || occurrences[0].Error.Unwrap().Error() != "connection reset" {
+		t.Errorf("unexpected filtered occurrences. Occurrences = %+v", occurrences)
+	}
+}
+
+func TestRecentErrorsHandlerJSON(t *testing.T) {
+	r := errx.NewRecentErrors(10)
+	r.Record(errx.NewError("ERR_1", "Resource not found", errx.WithNamespace("myapp"),
+		errx.AddMetadata("invoiceId", 42)).Trace(errx.Errorf("no rows")))
+	r.Record(errx.NewError("ERR_2", "Conflict", errx.WithNamespace("otherapp")))
+
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/debug/errx?format=json&namespace=myapp", nil))
+
+	var page struct {
+		Stats []struct {
+			Code  string `json:"code"`
+			Count int    `json:"count"`
+		} `json:"stats"`
+		Occurrences []struct {
+			Error struct {
+				Code     string                 `json:"code"`
+				Metadata map[string]interface{} `json:"metadata"`
+				Traces   []errx.TraceEntry      `json:"traces"`
+				Cause    struct {
+					Message string `json:"message"`
+				} `json:"cause"`
+			} `json:"error"`
+		} `json:"occurrences"`
+	}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Errorf("unexpected invalid json. Error = %s", err)
+		return
+	}
+
+	if len(page.Stats) != 1 || page.Stats[0].Code != "ERR_1" || len(page.Occurrences) != 1 {
+		t.Errorf("unexpected filtered page. Body = %s", w.Body)
+		return
+	}
+
+	e := page.Occurrences[0].Error
+	if e.Metadata["invoiceId"] != float64(42) || len(e.Traces) != 1 || e.Cause.Message != "no rows" {
+		t.Errorf("unexpected serialized error. Body = %s", w.Body)
+	}
+}
+
+func TestRecentErrorsHandlerHTML(t *testing.T) {
+	r := errx.NewRecentErrors(10)
+	r.Record(errx.NewError("ERR_1", "<script>alert(1)</script>", errx.WithNamespace("myapp")))
+
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/debug/errx", nil))
+
+	body := w.Body.String()
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("unexpected content type. ContentType = %s", ct)
+	}
+
+	if strings.Contains(body, "<script>") || !strings.Contains(body, "&lt;script&gt;") {
+		t.Errorf("unexpected unescaped html. Body = %s", body)
+	}
+}
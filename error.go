@@ -3,17 +3,24 @@ package errx
 import (
 	"errors"
 	"fmt"
-	"strings"
+	"time"
 )
 
 // NewError initiates a new error instance
 func NewError(code string, message string, args ...SetOptionFn) *Error {
+	err := newError(code, message, args...)
+	newHooks.fire(err, 1)
+	return err
+}
+
+// newError initiates a new error instance without calling hooks
+func newError(code string, message string, args ...SetOptionFn) *Error {
 	// Init error
 	err := &Error{
 		code:     code,
 		message:  message,
 		metadata: make(map[string]interface{}),
-		traces:   make([]string, 0),
+		traces:   make([]TraceEntry, 0),
 	}
 
 	// Evaluate options
@@ -24,6 +31,9 @@ func NewError(code string, message string, args ...SetOptionFn) *Error {
 		err.namespace = o.namespace
 	}
 
+	// Set exit code
+	err.exitCode = o.exitCode
+
 	// Set metadata value
 	if len(o.metadata) > 0 {
 		err.metadata = o.metadata
@@ -39,24 +49,31 @@ type Error struct {
 	namespace string
 	metadata  map[string]interface{}
 	sourceErr error
-	traces    []string
-	isSource  bool
+	traces    []TraceEntry
+	panicked  bool
+	exitCode  int
+	// conf is configuration of builder that owns the error, it overrides global configuration
+	conf *config
+	// mergedSource is true if traces of source error has been merged into traces
+	mergedSource bool
+	// collisions is metadata collisions recorded on Trace with MergeError policy
+	collisions Errors
 }
 
-// Error implement standard go error interface. If source error is exists then it will print error cause
+// Error implement standard go error interface. Error is printed by formatter set with WithFormatter,
+// or by DefaultFormatter which prints error with its traces and cause
 func (e *Error) Error() string {
-	errMsg := e.baseError()
-
-	if len(e.traces) > 0 {
-		errMsg += "\n  Traces => " + strings.Join(e.traces, "\n            ")
-	}
+	return e.Render()
+}
 
-	if e.sourceErr != nil {
-		// Append CausedBy and traces
-		errMsg += "\n  CausedBy => " + e.sourceErr.Error()
+// Render print error like Error, with configuration overridden by options, e.g. TracePath, frame filtering rules
+// or WithFormatter
+func (e *Error) Render(args ...SetOptionFn) string {
+	f := e.config(args...).formatter
+	if f == nil {
+		f = DefaultFormatter{}
 	}
-
-	return errMsg
+	return f.Format(e, args...)
 }
 
 // Unwrap implements xerrors.Wrapper interface
@@ -64,14 +81,21 @@ func (e *Error) Unwrap() error {
 	return e.sourceErr
 }
 
-// Copy duplicate error traces. Available options is WithNamespace, WithMetadata and CopySource
+// Copy duplicate error without traces. Metadata is deep copied, so nested maps and slices are not shared.
+// Available options is WithNamespace and WithMetadata
 func (e *Error) Copy(args ...SetOptionFn) *Error {
 	err := &Error{
 		code:      e.code,
 		message:   e.message,
 		namespace: e.namespace,
 		sourceErr: e.sourceErr,
-		traces:    []string{},
+		traces:    []TraceEntry{},
+		panicked:  e.panicked,
+		exitCode:  e.exitCode,
+		conf:      e.conf,
+
+		mergedSource: e.mergedSource,
+		collisions:   append(Errors(nil), e.collisions...),
 	}
 
 	o := evaluateOptions(args)
@@ -81,6 +105,11 @@ func (e *Error) Copy(args ...SetOptionFn) *Error {
 		err.namespace = o.namespace
 	}
 
+	// If exit code is set, then override exit code
+	if o.exitCode != 0 {
+		err.exitCode = o.exitCode
+	}
+
 	// If metadata is set, then override
 	if len(o.metadata) > 0 {
 		err.metadata = o.metadata
@@ -114,14 +143,44 @@ func (e *Error) Namespace() string {
 	return e.namespace
 }
 
-// Metadata is getter function to retrieve metadata value
+// Metadata is getter function to retrieve a deep copy of metadata value. Use Meta for read-only access without copying
 func (e *Error) Metadata() map[string]interface{} {
-	return e.metadata
+	return copyMetadata(e.metadata)
+}
+
+// Meta returns read-only view of metadata
+func (e *Error) Meta() MetadataView {
+	return MetadataView{m: e.metadata}
+}
+
+// Traces is getter function to retrieve traces value in "file:line" format. File path is formatted by
+// path formatter set with TracePath and frames are filtered by frame rules. Options override configuration
+func (e *Error) Traces(args ...SetOptionFn) []string {
+	return e.config(args...).traceLines(e.traces, false)
 }
 
-// Traces is getter function to retrieve traces value
-func (e *Error) Traces() []string {
-	return e.traces
+// TraceEntries is getter function to retrieve traces with its notes. File path is not formatted
+func (e *Error) TraceEntries() []TraceEntry {
+	return copyTraces(e.traces)
+}
+
+// Elapsed returns time elapsed between traces, sorted like Traces. Elapsed()[i] is the time between trace i+1
+// and trace i. Duration is zero if timestamp is not recorded, see RecordTime
+func (e *Error) Elapsed() []time.Duration {
+	if len(e.traces) < 2 {
+		return []time.Duration{}
+	}
+
+	durations := make([]time.Duration, len(e.traces)-1)
+	for i := range durations {
+		durations[i], _ = elapsed(e.traces, i)
+	}
+	return durations
+}
+
+// ExitCode is getter function to retrieve exit code value. It returns 0 if exit code is not set
+func (e *Error) ExitCode() int {
+	return e.exitCode
 }
 
 // Message is getter function to retrieve message value
@@ -129,7 +188,16 @@ func (e *Error) Message() string {
 	return e.message
 }
 
+// Wrap copy error and set err as source
 func (e *Error) Wrap(err error) *Error {
+	nErr := e.wrap(err)
+	if nErr != nil {
+		wrapHooks.fire(nErr, 1)
+	}
+	return nErr
+}
+
+func (e *Error) wrap(err error) *Error {
 	if err == nil {
 		return nil
 	}
@@ -139,11 +207,12 @@ func (e *Error) Wrap(err error) *Error {
 
 	// Set source
 	nErr.sourceErr = err
+	nErr.mergedSource = false
 
 	return nErr
 }
 
-func (e *Error) wrapAndTrace(srcErr error) (*Error, []string) {
+func (e *Error) wrapAndTrace(srcErr error) (*Error, []TraceEntry) {
 	// If srcErr is empty, then copy current error and its traces
 	if srcErr == nil {
 		return e.Copy(), copyTraces(e.traces)
@@ -152,7 +221,7 @@ func (e *Error) wrapAndTrace(srcErr error) (*Error, []string) {
 	// If srcErr error is equal to current error, Ignore source, copy current error and get traces from srcErr error
 	if errors.Is(srcErr, e) {
 		// Copy existing error and get traces from srcErr error
-		var traces []string
+		var traces []TraceEntry
 		var sErr *Error
 		ok := errors.As(srcErr, &sErr)
 		if ok {
@@ -162,19 +231,19 @@ func (e *Error) wrapAndTrace(srcErr error) (*Error, []string) {
 	}
 
 	// Init traces
-	traces := make([]string, 0)
+	traces := make([]TraceEntry, 0)
 
-	// If srcErr error is a *errx.Error, then wrap error and move traces to current error
+	// Wrap error
+	nErr := e.wrap(srcErr)
+
+	// If srcErr error is a *errx.Error, then copy traces to current error. Source error is left untouched,
+	// its traces will be omitted on printing CausedBy
 	if sErr, ok := srcErr.(*Error); ok && len(sErr.traces) > 0 {
-		// Copy traces
 		traces = copyTraces(sErr.traces)
-		// Remove traces from srcErr error
-		sErr.traces = nil
-		// Set error as source
-		sErr.isSource = true
+		nErr.mergedSource = true
 	}
 
-	return e.Wrap(srcErr), traces
+	return nErr, traces
 }
 
 func (e *Error) Trace(args ...SetOptionFn) *Error {
@@ -186,38 +255,48 @@ func (e *Error) Trace(args ...SetOptionFn) *Error {
 
 	// Get trace
 	ct := trace(o.skipTrace)
-	nErr.traces = []string{ct}
+	ct.Note = o.note
+
+	// Merge metadata, collision is recorded if policy is MergeError
+	if len(o.metadata) > 0 {
+		if keys := mergeMetadata(nErr.metadata, o.metadata, o.mergePolicy); len(keys) > 0 {
+			nErr.collisions = append(nErr.collisions, collisionErrors(keys, ct)...)
+		}
+	}
+
+	nErr.traces = []TraceEntry{ct}
 
 	// If traces is exists, then merge
 	if len(traces) > 0 {
 		nErr.traces = append(nErr.traces, traces...)
 	}
 
-	// Merge metadata
-	if len(o.metadata) > 0 {
-		for k, v := range o.metadata {
-			nErr.metadata[k] = v
-		}
-	}
+	traceHooks.fireAt(nErr, ct)
 
 	return nErr
 }
 
+// Collisions returns metadata collisions recorded on Trace with MergeError policy. Each collision is
+// MetadataCollisionError with the collided key in MetaCollisionKey metadata, so it can be checked with errors.Is
+func (e *Error) Collisions() Errors {
+	return append(Errors(nil), e.collisions...)
+}
+
 // AddMetadata copy existing error and set new metadata
 func (e *Error) AddMetadata(key string, value interface{}) *Error {
 	// Copy error
 	nErr := e.Copy()
 
 	// Add metadata
-	nErr.metadata[key] = value
+	nErr.metadata[key] = deepCopyValue(value)
 
 	return nErr
 }
 
 // baseError print base error message with its codes
-func (e *Error) baseError() string {
+func (e *Error) baseError(asSource bool) string {
 	if e.namespace == "" {
-		if e.isSource {
+		if asSource {
 			return fmt.Sprintf("[%s] %s", e.code, e.message)
 		}
 		return e.message
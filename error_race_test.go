@@ -0,0 +1,78 @@
+package errx_test
+
+import (
+	"errors"
+	"github.com/nbs-go/errx"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTraceDoesNotMutateSource(t *testing.T) {
+	srcErr := errx.NewError("ERR_1", "customer.email is required").Trace()
+	srcMsg := srcErr.Error()
+
+	err := errx.NewError("ERR_2", "Failed to create customer").Trace(errx.Source(srcErr))
+
+	if traces := srcErr.Traces(); len(traces) != 1 {
+		t.Errorf("unexpected source traces is modified. Traces = %+v", traces)
+	}
+
+	if msg := srcErr.Error(); msg != srcMsg {
+		t.Errorf("unexpected source output is modified. Error = %s", msg)
+	}
+
+	if traces := err.Traces(); len(traces) != 2 {
+		t.Errorf("unexpected merged traces length. Length = %d", len(traces))
+	}
+
+	msgs := strings.Split(err.Error(), "\n")
+	if m := msgs[len(msgs)-1]; m != "  CausedBy => [ERR_1] customer.email is required" {
+		t.Errorf("unexpected cause. Caused By = %s", m)
+	}
+}
+
+func TestConcurrentTraceSharedError(t *testing.T) {
+	sharedErr := errx.NewError("ERR_1", "Resource not found", errx.AddMetadata("httpStatus", 404)).Trace()
+
+	var wg sync.WaitGroup
+	results := make([]*errx.Error, 50)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := errx.NewError("ERR_2", "Failed to load invoice").
+				Trace(errx.Source(sharedErr), errx.AddMetadata("index", i))
+			results[i] = err.Trace()
+			_ = results[i].Error()
+		}(i)
+	}
+	wg.Wait()
+
+	if traces := sharedErr.Traces(); len(traces) != 1 {
+		t.Errorf("unexpected shared error traces is modified. Traces = %+v", traces)
+	}
+
+	for _, err := range results {
+		if traces := err.Traces(); len(traces) != 3 {
+			t.Errorf("unexpected traces length. Length = %d", len(traces))
+		}
+	}
+}
+
+func TestConcurrentGlobalTraceSharedError(t *testing.T) {
+	sharedErr := errx.NewError("ERR_1", "Resource not found").Trace(errx.Errorf("record not found"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := errx.Trace(sharedErr)
+			if !errors.Is(err, sharedErr) {
+				t.Errorf("unexpected traced error. Error = %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,138 @@
+package errx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Fingerprint returns a stable hash of error chain for grouping occurrences of the same error.
+// Hash is built from namespace, code, message and normalized trace frames of each *errx.Error in chain.
+// Frames are normalized to parent directory and file name, and line numbers are excluded by default to tolerate
+// line drift between releases. Metadata is excluded unless set with FingerprintMetadata.
+// For non *errx.Error in chain, only its type is used since message may contain volatile values
+func Fingerprint(err error, args ...FingerprintOptionFn) string {
+	if err == nil {
+		return ""
+	}
+
+	o := evaluateFingerprintOptions(args)
+
+	h := sha256.New()
+	walk(err, func(err error) bool {
+		xErr, ok := err.(*Error)
+		if !ok {
+			_, _ = fmt.Fprintf(h, "type=%T\n", err)
+			return false
+		}
+
+		_, _ = fmt.Fprintf(h, "ns=%s\ncode=%s\nmsg=%s\n", xErr.namespace, xErr.code, xErr.message)
+		for _, t := range xErr.traces {
+			_, _ = fmt.Fprintf(h, "frame=%s\n", normalizeFrame(t, o.lines))
+		}
+		for _, k := range o.metadataKeys {
+			if v, ok := xErr.metadata[k]; ok {
+				_, _ = fmt.Fprintf(h, "meta.%s=%v\n", k, v)
+			}
+		}
+		return false
+	})
+
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// normalizeFrame trims absolute path of trace to parent directory and file name
+func normalizeFrame(t TraceEntry, withLine bool) string {
+	dir, base := path.Split(filepath.ToSlash(t.File))
+	frame := path.Join(path.Base(dir), base)
+	if withLine {
+		frame += ":" + strconv.Itoa(t.Line)
+	}
+	return frame
+}
+
+// NewDeduper creates Deduper that suppresses errors with the same fingerprint within window
+func NewDeduper(window time.Duration, args ...FingerprintOptionFn) *Deduper {
+	return &Deduper{
+		window: window,
+		args:   args,
+		seen:   make(map[string]time.Time),
+	}
+}
+
+// Deduper drops repeated errors by its fingerprint within a time window. It is intended to be used before logging
+type Deduper struct {
+	window    time.Duration
+	args      []FingerprintOptionFn
+	mu        sync.Mutex
+	seen      map[string]time.Time
+	lastSweep time.Time
+}
+
+// Allow returns true if error fingerprint has not been seen within window, which means the error should be logged
+func (d *Deduper) Allow(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	fp := Fingerprint(err, d.args...)
+	now := time.Now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.sweep(now)
+
+	if last, ok := d.seen[fp]; ok && now.Sub(last) < d.window {
+		return false
+	}
+	d.seen[fp] = now
+	return true
+}
+
+// sweep removes expired fingerprints, at most once per window
+func (d *Deduper) sweep(now time.Time) {
+	if now.Sub(d.lastSweep) < d.window {
+		return
+	}
+	for fp, last := range d.seen {
+		if now.Sub(last) >= d.window {
+			delete(d.seen, fp)
+		}
+	}
+	d.lastSweep = now
+}
+
+// FingerprintLines includes line numbers of trace frames in fingerprint
+func FingerprintLines() FingerprintOptionFn {
+	return func(o *fingerprintOptions) {
+		o.lines = true
+	}
+}
+
+// FingerprintMetadata includes stable metadata values in fingerprint
+func FingerprintMetadata(keys ...string) FingerprintOptionFn {
+	return func(o *fingerprintOptions) {
+		o.metadataKeys = append(o.metadataKeys, keys...)
+	}
+}
+
+type fingerprintOptions struct {
+	lines        bool
+	metadataKeys []string
+}
+
+type FingerprintOptionFn = func(*fingerprintOptions)
+
+func evaluateFingerprintOptions(args []FingerprintOptionFn) *fingerprintOptions {
+	o := new(fingerprintOptions)
+	for _, fn := range args {
+		fn(o)
+	}
+	return o
+}
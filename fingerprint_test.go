@@ -0,0 +1,81 @@
+package errx_test
+
+import (
+	"fmt"
+	"github.com/nbs-go/errx"
+	"testing"
+	"time"
+)
+
+var notFoundErr = errx.NewError("ERR_1", "Resource not found", errx.WithNamespace("myapp"))
+
+func findInvoice(id int) error {
+	return notFoundErr.Trace(errx.AddMetadata("invoiceId", id), errx.Errorf("record %d not found", id))
+}
+
+func TestFingerprintStable(t *testing.T) {
+	fp1 := errx.Fingerprint(findInvoice(1))
+	fp2 := errx.Fingerprint(findInvoice(2))
+
+	if fp1 == "" || fp1 != fp2 {
+		t.Errorf("unexpected fingerprint differs by volatile values. Fingerprint1 = %s, Fingerprint2 = %s", fp1, fp2)
+	}
+
+	if fp := errx.Fingerprint(errx.Trace(findInvoice(1))); fp == fp1 {
+		t.Errorf("unexpected fingerprint equal for different trace frames")
+	}
+
+	if fp := errx.Fingerprint(notFoundErr.Copy(errx.WithNamespace("other")).Trace()); fp == fp1 {
+		t.Errorf("unexpected fingerprint equal for different namespace")
+	}
+
+	if fp := errx.Fingerprint(nil); fp != "" {
+		t.Errorf("unexpected fingerprint of nil error. Fingerprint = %s", fp)
+	}
+}
+
+func TestFingerprintOptions(t *testing.T) {
+	err1 := findInvoice(1)
+	err2 := findInvoice(2)
+
+	if errx.Fingerprint(err1, errx.FingerprintMetadata("invoiceId")) == errx.Fingerprint(err2, errx.FingerprintMetadata("invoiceId")) {
+		t.Errorf("unexpected fingerprint equal when metadata is included")
+	}
+
+	// Same file, different lines
+	err3 := notFoundErr.Trace()
+	err4 := notFoundErr.Trace()
+	if errx.Fingerprint(err3) != errx.Fingerprint(err4) {
+		t.Errorf("unexpected fingerprint differs by line")
+	}
+
+	if errx.Fingerprint(err3, errx.FingerprintLines()) == errx.Fingerprint(err4, errx.FingerprintLines()) {
+		t.Errorf("unexpected fingerprint equal when lines is included")
+	}
+}
+
+func TestDeduper(t *testing.T) {
+	d := errx.NewDeduper(50 * time.Millisecond)
+
+	if !d.Allow(findInvoice(1)) {
+		t.Errorf("unexpected first occurrence is suppressed")
+	}
+
+	if d.Allow(findInvoice(2)) {
+		t.Errorf("unexpected repeated occurrence is allowed")
+	}
+
+	if !d.Allow(fmt.Errorf("connection reset")) {
+		t.Errorf("unexpected different error is suppressed")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if !d.Allow(findInvoice(3)) {
+		t.Errorf("unexpected occurrence after window is suppressed")
+	}
+
+	if d.Allow(nil) {
+		t.Errorf("unexpected nil error is allowed")
+	}
+}
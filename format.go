@@ -0,0 +1,184 @@
+package errx
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Formatter formats message returned by Error. Options are set on Render call to override configuration,
+// formatter should pass them to Traces, so traces are rendered with the same configuration
+type Formatter interface {
+	Format(e *Error, args ...SetOptionFn) string
+}
+
+// FormatterFunc is an adapter to use function as Formatter
+type FormatterFunc func(e *Error, args ...SetOptionFn) string
+
+// Format calls f(e, args...)
+func (f FormatterFunc) Format(e *Error, args ...SetOptionFn) string {
+	return f(e, args...)
+}
+
+// WithFormatter set formatter of Error output. It can be set globally with Configure, per Builder,
+// or on Render call
+func WithFormatter(f Formatter) SetOptionFn {
+	return func(o *options) {
+		o.formatter = f
+	}
+}
+
+// BaseErrorFunc prints code, namespace and message of error. AsSource is true if error is printed as a cause
+type BaseErrorFunc func(e *Error, asSource bool) string
+
+// DefaultBaseError prints "namespace: [code] message". If namespace is empty, code is only printed if error is
+// printed as a cause
+func DefaultBaseError(e *Error, asSource bool) string {
+	return e.baseError(asSource)
+}
+
+// BaseErrorFormatter is implemented by formatter that can override how code, namespace and message is printed.
+// Base error of formatter configured for the error is also used by ShortMessage, Logfmt and RecordError
+type BaseErrorFormatter interface {
+	FormatBase(e *Error, asSource bool) string
+}
+
+// formatBase prints base error with formatter configured for the error
+func (e *Error) formatBase(asSource bool) string {
+	if f, ok := e.config().formatter.(BaseErrorFormatter); ok {
+		return f.FormatBase(e, asSource)
+	}
+	return e.baseError(asSource)
+}
+
+// DefaultFormatter prints error in multiple lines, with its traces and cause:
+//
+//	myapp: [E_NOT_FOUND] Invoice not found
+//	  Traces => /app/invoice.go:42
+//	  CausedBy => no rows
+type DefaultFormatter struct {
+	// BaseError overrides how code, namespace and message is printed. Default is DefaultBaseError
+	BaseError BaseErrorFunc
+	// Metadata prints metadata sorted by key after base error
+	Metadata bool
+}
+
+// Format implements Formatter interface
+func (f DefaultFormatter) Format(e *Error, args ...SetOptionFn) string {
+	return f.format(e, false, args, nil)
+}
+
+// FormatBase implements BaseErrorFormatter interface
+func (f DefaultFormatter) FormatBase(e *Error, asSource bool) string {
+	return baseErrorOrDefault(f.BaseError)(e, asSource)
+}
+
+// format print error with its traces and cause. If asSource is true, then error is printed as a cause
+// whose traces has been merged into the wrapper error. Options override configuration on rendering traces.
+// Frames that are shared with traces of enclosing error are printed as "... N more"
+func (f DefaultFormatter) format(e *Error, asSource bool, args []SetOptionFn, enclosing []TraceEntry) string {
+	errMsg := f.FormatBase(e, asSource)
+
+	if f.Metadata && len(e.metadata) > 0 {
+		errMsg += "\n  Metadata => " + strings.Join(metadataPairs(e.metadata), "\n              ")
+	}
+
+	if !asSource && len(e.traces) > 0 {
+		lines := e.config(args...).enclosedTraceLines(e.traces, enclosing)
+		errMsg += "\n  Traces => " + strings.Join(lines, "\n            ")
+		enclosing = e.traces
+	}
+
+	if e.sourceErr != nil {
+		// Append CausedBy and traces
+		if sErr, ok := e.sourceErr.(*Error); ok {
+			errMsg += "\n  CausedBy => " + f.format(sErr, e.mergedSource, args, enclosing)
+		} else {
+			errMsg += "\n  CausedBy => " + e.sourceErr.Error()
+		}
+	}
+
+	return errMsg
+}
+
+// SingleLineFormatter prints error chain in a single line without traces, e.g.
+// "myapp: [E_NOT_FOUND] Invoice not found: Internal Error: no rows". Aggregated Errors, e.g. failures of Group,
+// are printed in the same line separated by "; "
+type SingleLineFormatter struct {
+	// BaseError overrides how code, namespace and message is printed. Default is base error of formatter
+	// configured for each error in chain
+	BaseError BaseErrorFunc
+}
+
+// Format implements Formatter interface
+func (f SingleLineFormatter) Format(e *Error, _ ...SetOptionFn) string {
+	return f.message(e)
+}
+
+// message prints messages of error chain joined with ": "
+func (f SingleLineFormatter) message(err error) string {
+	msgs := make([]string, 0)
+	for err != nil {
+		switch tErr := err.(type) {
+		case *Error:
+			if f.BaseError != nil {
+				msgs = append(msgs, f.BaseError(tErr, false))
+			} else {
+				msgs = append(msgs, tErr.formatBase(false))
+			}
+			err = tErr.sourceErr
+			continue
+		case Errors:
+			aggregated := make([]string, len(tErr))
+			for i, xErr := range tErr {
+				aggregated[i] = f.message(xErr)
+			}
+			msgs = append(msgs, strings.Join(aggregated, "; "))
+		default:
+			// Generic error message already contains its wrapped errors
+			msgs = append(msgs, err.Error())
+		}
+		break
+	}
+	return strings.Join(msgs, ": ")
+}
+
+// FormatBase implements BaseErrorFormatter interface
+func (f SingleLineFormatter) FormatBase(e *Error, asSource bool) string {
+	return baseErrorOrDefault(f.BaseError)(e, asSource)
+}
+
+// JSONFormatter prints error chain as JSON, as serialized by MarshalJSON
+type JSONFormatter struct{}
+
+// Format implements Formatter interface
+func (JSONFormatter) Format(e *Error, _ ...SetOptionFn) string {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return DefaultFormatter{}.Format(e)
+	}
+	return string(b)
+}
+
+func baseErrorOrDefault(fn BaseErrorFunc) BaseErrorFunc {
+	if fn == nil {
+		return DefaultBaseError
+	}
+	return fn
+}
+
+// metadataPairs returns metadata in "key=value" format sorted by key
+func metadataPairs(metadata map[string]interface{}) []string {
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", k, metadata[k])
+	}
+	return pairs
+}
@@ -0,0 +1,86 @@
+package errx_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/nbs-go/errx"
+	"strings"
+	"testing"
+)
+
+func TestFormatter(t *testing.T) {
+	b := errx.NewBuilder("myapp", errx.WithFormatter(errx.SingleLineFormatter{}))
+	notFound := b.NewError("E_NOT_FOUND", "Invoice not found")
+
+	err := notFound.Trace(errx.Source(errx.Trace(errors.New("no rows"))), errx.AddMetadata("invoiceId", 42))
+
+	if msg := err.Error(); msg != "myapp: [E_NOT_FOUND] Invoice not found: Internal Error: no rows" {
+		t.Errorf("unexpected single line message. Message = %s", msg)
+	}
+
+	// Override formatter on call
+	expected := "myapp: [E_NOT_FOUND] Invoice not found" +
+		"\n  Metadata => invoiceId=42" +
+		"\n  Traces => format_test.go:16" +
+		"\n            format_test.go:16" +
+		"\n  CausedBy => [ERROR] Internal Error" +
+		"\n  CausedBy => no rows"
+	msg := err.Render(errx.WithFormatter(errx.DefaultFormatter{Metadata: true}), errx.TracePath(errx.BaseName))
+	if msg != expected {
+		t.Errorf("unexpected message with metadata. Message = %s", msg)
+	}
+
+	var decoded map[string]interface{}
+	if jErr := json.Unmarshal([]byte(err.Render(errx.WithFormatter(errx.JSONFormatter{}))), &decoded); jErr != nil ||
+		decoded["code"] != "E_NOT_FOUND" {
+		t.Errorf("unexpected json message. Error = %v, Decoded = %v", jErr, decoded)
+	}
+}
+
+func TestFormatterBaseError(t *testing.T) {
+	baseError := func(e *errx.Error, asSource bool) string {
+		return fmt.Sprintf("%s (%s)", e.Message(), e.Code())
+	}
+
+	err := errx.NewError("ERR_1", "Bad Request").Trace(errx.Source(errors.New("invalid email")))
+
+	msg := err.Render(errx.WithFormatter(errx.DefaultFormatter{BaseError: baseError}))
+	if !strings.HasPrefix(msg, "Bad Request (ERR_1)\n  Traces => ") {
+		t.Errorf("unexpected message with custom base error. Message = %s", msg)
+	}
+
+	msg = err.Render(errx.WithFormatter(errx.SingleLineFormatter{BaseError: baseError}))
+	if msg != "Bad Request (ERR_1): invalid email" {
+		t.Errorf("unexpected single line message with custom base error. Message = %s", msg)
+	}
+
+	// Base error of builder formatter is used by other renderers
+	b := errx.NewBuilder("myapp", errx.WithFormatter(errx.DefaultFormatter{BaseError: baseError}))
+	notFound := b.NewError("E_NOT_FOUND", "Invoice not found")
+	bErr := b.NewError("E_LOAD", "Failed to load invoice").Trace(errx.Source(notFound))
+
+	if msg = errx.ShortMessage(bErr); msg != "Failed to load invoice (E_LOAD): Invoice not found (E_NOT_FOUND)" {
+		t.Errorf("unexpected short message with custom base error. Message = %s", msg)
+	}
+
+	if msg = errx.Logfmt(bErr); !strings.Contains(msg, `err.cause="Invoice not found (E_NOT_FOUND)"`) {
+		t.Errorf("unexpected logfmt cause with custom base error. Message = %s", msg)
+	}
+
+	if msg = errx.ExceptionAttributes(bErr)[errx.AttrExceptionMessage]; msg != "Failed to load invoice (E_LOAD)" {
+		t.Errorf("unexpected exception message with custom base error. Message = %s", msg)
+	}
+}
+
+func TestConfigureFormatter(t *testing.T) {
+	custom := errx.FormatterFunc(func(e *errx.Error, _ ...errx.SetOptionFn) string {
+		return "custom " + e.Code()
+	})
+	errx.Configure(append(testConfig, errx.WithFormatter(custom))...)
+	defer errx.Configure(testConfig...)
+
+	if msg := errx.InternalError().Error(); msg != "custom ERROR" {
+		t.Errorf("unexpected message of global formatter. Message = %s", msg)
+	}
+}
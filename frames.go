@@ -0,0 +1,90 @@
+package errx
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FrameFilter returns true if trace frame should be printed
+type FrameFilter func(t TraceEntry) bool
+
+// frameRules filters frames printed in Traces and Error output. Nil rules keep all frames
+type frameRules struct {
+	filters  []FrameFilter
+	collapse bool
+}
+
+// FilterFrames prints only frames that pass filter
+func FilterFrames(f FrameFilter) SetOptionFn {
+	return func(o *options) {
+		rules := o.frameRules()
+		rules.filters = append(rules.filters, f)
+	}
+}
+
+// DropPackages drops frames of packages, including its sub-packages, e.g. DropPackages("runtime", "net/http")
+func DropPackages(prefixes ...string) SetOptionFn {
+	return FilterFrames(func(t TraceEntry) bool {
+		pkg := packagePath(t)
+		for _, p := range prefixes {
+			if hasPathPrefix(pkg, p) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// MainModuleOnly drops frames outside main module. Frames are kept if main module is unknown
+func MainModuleOnly() SetOptionFn {
+	return FilterFrames(func(t TraceEntry) bool {
+		mod := mainModule()
+		return mod == "" || hasPathPrefix(packagePath(t), mod)
+	})
+}
+
+// CollapseFrames prints consecutive dropped frames as "... N frames omitted"
+func CollapseFrames() SetOptionFn {
+	return func(o *options) {
+		o.frameRules().collapse = true
+	}
+}
+
+// AllFrames prints all frames. It is used to disable frame rules set globally or in Builder
+func AllFrames() SetOptionFn {
+	return func(o *options) {
+		o.frames = &frameRules{}
+	}
+}
+
+func (o *options) frameRules() *frameRules {
+	if o.frames == nil {
+		o.frames = &frameRules{}
+	}
+	return o.frames
+}
+
+// keep check if frame pass all filters. Frame with unknown package is always kept
+func (r *frameRules) keep(t TraceEntry) bool {
+	if r == nil || t.Function == "" {
+		return true
+	}
+	for _, f := range r.filters {
+		if !f(t) {
+			return false
+		}
+	}
+	return true
+}
+
+func omittedFrames(n int) string {
+	if n == 1 {
+		return "... 1 frame omitted"
+	}
+	return "... " + strconv.Itoa(n) + " frames omitted"
+}
+
+// hasPathPrefix check if import path is prefix or equal to path by its elements
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
@@ -0,0 +1,66 @@
+package errx_test
+
+import (
+	"github.com/nbs-go/errx"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func panicInSort() (err error) {
+	defer errx.Recover(&err)
+	sort.Slice([]int{2, 1}, func(i, j int) bool {
+		panic("boom")
+	})
+	return nil
+}
+
+func TestFilterFrames(t *testing.T) {
+	xErr := panicInSort().(*errx.Error)
+
+	all := xErr.Traces()
+	if len(all) < 4 || !strings.HasPrefix(all[0], "github.com/nbs-go/errx/frames_test.go:") {
+		t.Errorf("unexpected panic traces. Traces = %v", all)
+		return
+	}
+
+	dropped := xErr.Traces(errx.DropPackages("sort", "testing"))
+	for _, trace := range dropped {
+		if !strings.HasPrefix(trace, "github.com/nbs-go/errx/") {
+			t.Errorf("unexpected dropped frame is printed. Trace = %s", trace)
+		}
+	}
+
+	if mainOnly := xErr.Traces(errx.MainModuleOnly()); len(mainOnly) != len(dropped) {
+		t.Errorf("unexpected main module frames. Traces = %v", mainOnly)
+	}
+
+	collapsed := xErr.Traces(errx.MainModuleOnly(), errx.CollapseFrames())
+	omitted := regexp.MustCompile(`^\.\.\. \d+ frames? omitted$`)
+	if len(collapsed) < 2 || !omitted.MatchString(collapsed[1]) || !omitted.MatchString(collapsed[len(collapsed)-1]) {
+		t.Errorf("unexpected collapsed frames. Traces = %v", collapsed)
+	}
+
+	if msg := xErr.Render(errx.MainModuleOnly(), errx.CollapseFrames()); !strings.Contains(msg, "\n            ... ") {
+		t.Errorf("unexpected collapsed frames in message. Message = %s", msg)
+	}
+}
+
+func TestBuilderFilterFrames(t *testing.T) {
+	b := errx.NewBuilder("myapp", errx.DropPackages("github.com/nbs-go/errx"), errx.CollapseFrames())
+	err := b.NewError("E_NOT_FOUND", "Not found").Trace()
+
+	if traces := err.Traces(); len(traces) != 1 || traces[0] != "... 1 frame omitted" {
+		t.Errorf("unexpected builder filtered traces. Traces = %v", traces)
+	}
+
+	// Override builder rules on call
+	if traces := err.Traces(errx.AllFrames()); len(traces) != 1 || !strings.HasSuffix(traces[0], "frames_test.go:52") {
+		t.Errorf("unexpected traces with all frames. Traces = %v", traces)
+	}
+
+	if msg := err.Render(errx.AllFrames()); !strings.Contains(msg, "Traces => github.com/nbs-go/errx/frames_test.go:52") {
+		t.Errorf("unexpected message with all frames. Message = %s", msg)
+	}
+}
@@ -0,0 +1,208 @@
+package errx
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// MetaTask is metadata key of task name that failed in Group
+	MetaTask = "task"
+	// MetaTaskIndex is metadata key of task index that failed in Group
+	MetaTaskIndex = "taskIndex"
+)
+
+// NewGroup creates a new Group and a derived context. Derived context is canceled when Wait returns,
+// or when first task fails if CancelOnError option is set
+func NewGroup(ctx context.Context, args ...GroupOptionFn) (*Group, context.Context) {
+	o := evaluateGroupOptions(args)
+
+	ctx, cancel := context.WithCancel(ctx)
+	g := &Group{
+		cancel:        cancel,
+		cancelOnError: o.cancelOnError,
+	}
+
+	if o.limit > 0 {
+		g.sem = make(chan struct{}, o.limit)
+	}
+
+	return g, ctx
+}
+
+// Group runs tasks in goroutines and collects all failures as traced *errx.Error.
+// Unlike errgroup, failures are not discarded after the first one
+type Group struct {
+	cancel        context.CancelFunc
+	cancelOnError bool
+	sem           chan struct{}
+	wg            sync.WaitGroup
+	mu            sync.Mutex
+	count         int
+	errs          Errors
+}
+
+// Go runs fn in a new goroutine. Failure is tagged with task index. If concurrency limit is set,
+// Go blocks until a slot is available
+func (g *Group) Go(fn func() error) {
+	g.start(trace(1), "", fn)
+}
+
+// GoNamed runs fn in a new goroutine. Failure is tagged with task name and index
+func (g *Group) GoNamed(name string, fn func() error) {
+	g.start(trace(1), name, fn)
+}
+
+// start runs task in a new goroutine. Site is where task is started, it is traced on failure that is not *errx.Error
+func (g *Group) start(site TraceEntry, name string, fn func() error) {
+	g.mu.Lock()
+	idx := g.count
+	g.count++
+	g.mu.Unlock()
+
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+
+		var err error
+		func() {
+			defer Recover(&err)
+			err = fn()
+		}()
+
+		if err != nil {
+			g.fail(idx, name, site, err)
+		}
+	}()
+}
+
+// Wait blocks until all tasks are finished. If one or more tasks failed, then it returns GroupFailedError
+// with all failures as source
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+
+	if len(g.errs) == 0 {
+		return nil
+	}
+
+	// Sort failures by task index, so output is deterministic
+	errs := make(Errors, len(g.errs))
+	copy(errs, g.errs)
+	sort.SliceStable(errs, func(i, j int) bool {
+		a, _ := errs[i].Meta().GetInt(MetaTaskIndex)
+		b, _ := errs[j].Meta().GetInt(MetaTaskIndex)
+		return a < b
+	})
+
+	// Wrap explicitly instead of Trace, since Trace treats failures of a nested Group as the same error
+	// and drops them
+	nErr := GroupFailedError.wrap(errs)
+	ct := trace(1)
+	nErr.traces = []TraceEntry{ct}
+	traceHooks.fireAt(nErr, ct)
+
+	return nErr
+}
+
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
+
+func (g *Group) fail(idx int, name string, site TraceEntry, err error) {
+	// If error is not *errx.Error then it will be wrapped into InternalError, traced where task is started
+	var tErr *Error
+	if xErr, ok := err.(*Error); ok {
+		// Copy without adding trace inside Group, so only traces of task are kept
+		tErr = xErr.Copy()
+		tErr.traces = copyTraces(xErr.traces)
+	} else {
+		tErr = InternalError().wrap(err)
+		tErr.traces = []TraceEntry{site}
+	}
+
+	// Tag error with task
+	tErr.metadata[MetaTaskIndex] = idx
+	if name != "" {
+		tErr.metadata[MetaTask] = name
+	}
+
+	g.mu.Lock()
+	g.errs = append(g.errs, tErr)
+	g.mu.Unlock()
+
+	if g.cancelOnError {
+		g.cancel()
+	}
+}
+
+// Errors is a list of errors that is aggregated as one error
+type Errors []*Error
+
+// Error implement standard go error interface. Each error is printed in new line
+func (errs Errors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Is check if any of aggregated errors matches target, so it can be inspected by errors.Is
+func (errs Errors) Is(target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first aggregated error that matches target, so it can be inspected by errors.As
+func (errs Errors) As(target interface{}) bool {
+	for _, err := range errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// CancelOnError set Group to cancel its context when first task fails
+func CancelOnError() GroupOptionFn {
+	return func(o *groupOptions) {
+		o.cancelOnError = true
+	}
+}
+
+// Limit set maximum number of tasks that run concurrently in Group
+func Limit(n int) GroupOptionFn {
+	return func(o *groupOptions) {
+		o.limit = n
+	}
+}
+
+type groupOptions struct {
+	cancelOnError bool
+	limit         int
+}
+
+type GroupOptionFn = func(*groupOptions)
+
+func evaluateGroupOptions(args []GroupOptionFn) *groupOptions {
+	o := new(groupOptions)
+	for _, fn := range args {
+		fn(o)
+	}
+	return o
+}
@@ -0,0 +1,187 @@
+package errx_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nbs-go/errx"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroupCollectAllErrors(t *testing.T) {
+	g, _ := errx.NewGroup(context.Background())
+
+	notFoundErr := errx.NewError("ERR_1", "Resource not found")
+
+	g.GoNamed("loadInvoice", func() error {
+		return notFoundErr
+	})
+	g.Go(func() error {
+		return nil
+	})
+	g.Go(func() error {
+		return fmt.Errorf("connection reset")
+	})
+
+	err := g.Wait()
+	if !errors.Is(err, errx.GroupFailedError) {
+		t.Errorf("unexpected aggregated error. Error = %v", err)
+		return
+	}
+
+	var errs errx.Errors
+	if !errors.As(err, &errs) {
+		t.Errorf("unexpected aggregated error source. Error = %s", err)
+		return
+	}
+
+	if len(errs) != 2 {
+		t.Errorf("unexpected failures length. Length = %d", len(errs))
+		return
+	}
+
+	if m := errs[0].Metadata(); m[errx.MetaTask] != "loadInvoice" || m[errx.MetaTaskIndex] != 0 {
+		t.Errorf("unexpected first failure metadata. Metadata = %+v", m)
+	}
+
+	if m := errs[1].Metadata(); m[errx.MetaTaskIndex] != 2 {
+		t.Errorf("unexpected second failure metadata. Metadata = %+v", m)
+	}
+
+	if !errors.Is(errs[1], errx.InternalError()) {
+		t.Errorf("unexpected generic failure is not wrapped as InternalError. Error = %s", errs[1])
+	}
+
+	// Generic failure is traced where task is started
+	if traces := errs[1].Traces(); len(traces) != 1 || traces[0] != "github.com/nbs-go/errx/group_test.go:25" {
+		t.Errorf("unexpected traces on generic failure. Traces = %v", traces)
+	}
+
+	// Group does not add its own trace to failures
+	if traces := errs[0].Traces(); len(traces) != 0 {
+		t.Errorf("unexpected traces on failure. Traces = %v", traces)
+	}
+
+	if !errors.Is(err, notFoundErr) {
+		t.Errorf("unexpected failure is not found in aggregated error")
+	}
+
+	t.Logf("Error = %s", err)
+}
+
+func TestGroupNested(t *testing.T) {
+	notFoundErr := errx.NewError("ERR_1", "Resource not found")
+
+	g, _ := errx.NewGroup(context.Background())
+	g.Go(func() error {
+		inner, _ := errx.NewGroup(context.Background())
+		inner.Go(func() error {
+			return notFoundErr
+		})
+		return inner.Wait()
+	})
+	g.Go(func() error {
+		return fmt.Errorf("connection reset")
+	})
+
+	err := g.Wait()
+
+	var errs errx.Errors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Errorf("unexpected failures of outer group. Error = %v", err)
+		return
+	}
+
+	if !errors.Is(errs[0], errx.GroupFailedError) || !errors.Is(err, notFoundErr) {
+		t.Errorf("unexpected failure of inner group is dropped. Error = %s", err)
+	}
+
+	if !strings.Contains(err.Error(), "connection reset") {
+		t.Errorf("unexpected failure of outer task is dropped. Error = %s", err)
+	}
+}
+
+func TestGroupNoError(t *testing.T) {
+	g, ctx := errx.NewGroup(context.Background())
+	g.Go(func() error {
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		t.Errorf("unexpected error. Error = %s", err)
+	}
+
+	if ctx.Err() == nil {
+		t.Errorf("unexpected context is not canceled after Wait")
+	}
+}
+
+func TestGroupCancelOnError(t *testing.T) {
+	g, ctx := errx.NewGroup(context.Background(), errx.CancelOnError())
+
+	g.Go(func() error {
+		return errx.InternalError()
+	})
+	g.Go(func() error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return nil
+		}
+	})
+
+	err := g.Wait()
+
+	var errs errx.Errors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Errorf("unexpected failures. Error = %v", err)
+		return
+	}
+
+	if !errors.Is(errs[1], context.Canceled) {
+		t.Errorf("unexpected second task is not canceled. Error = %s", errs[1])
+	}
+}
+
+func TestGroupLimit(t *testing.T) {
+	g, _ := errx.NewGroup(context.Background(), errx.Limit(2))
+
+	var running, maxRunning int32
+	for i := 0; i < 10; i++ {
+		g.Go(func() error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Errorf("unexpected error. Error = %s", err)
+	}
+
+	if maxRunning > 2 {
+		t.Errorf("unexpected concurrent tasks exceeds limit. Max = %d", maxRunning)
+	}
+}
+
+func TestGroupPanic(t *testing.T) {
+	g, _ := errx.NewGroup(context.Background())
+	g.Go(func() error {
+		panic("task failure")
+	})
+
+	if err := g.Wait(); !errx.IsPanic(err) {
+		t.Errorf("unexpected panic is not recovered. Error = %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package errx
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Hook observes error lifecycle. It receives the resulting error and call site in "file:line" format.
+// Hook is called synchronously, so it must be fast and must not block
+type Hook func(err *Error, callSite string)
+
+var (
+	newHooks   hookRegistry
+	traceHooks hookRegistry
+	wrapHooks  hookRegistry
+)
+
+// OnNew registers hook that is called when an error is created by NewError or Builder.NewError.
+// It returns function to remove the hook
+func OnNew(h Hook) (remove func()) {
+	return newHooks.add(h)
+}
+
+// OnTrace registers hook that is called when an error is traced by Error.Trace or Trace.
+// It returns function to remove the hook
+func OnTrace(h Hook) (remove func()) {
+	return traceHooks.add(h)
+}
+
+// OnWrap registers hook that is called when an error is wrapped by Error.Wrap, Wrap or Builder.Wrap.
+// It returns function to remove the hook
+func OnWrap(h Hook) (remove func()) {
+	return wrapHooks.add(h)
+}
+
+type hookEntry struct {
+	id   uint64
+	hook Hook
+}
+
+// hookRegistry is a copy-on-write list of hooks. Firing hooks only cost an atomic load if no hook is registered
+type hookRegistry struct {
+	mu     sync.Mutex
+	lastID uint64
+	count  int32
+	hooks  atomic.Value
+}
+
+func (r *hookRegistry) add(h Hook) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	id := r.lastID
+
+	hooks, _ := r.hooks.Load().([]hookEntry)
+	nHooks := make([]hookEntry, len(hooks), len(hooks)+1)
+	copy(nHooks, hooks)
+	r.store(append(nHooks, hookEntry{id: id, hook: h}))
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.remove(id)
+		})
+	}
+}
+
+func (r *hookRegistry) remove(id uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hooks, _ := r.hooks.Load().([]hookEntry)
+	nHooks := make([]hookEntry, 0, len(hooks))
+	for _, e := range hooks {
+		if e.id != id {
+			nHooks = append(nHooks, e)
+		}
+	}
+	r.store(nHooks)
+}
+
+// store set hooks and count. Caller must hold lock
+func (r *hookRegistry) store(hooks []hookEntry) {
+	r.hooks.Store(hooks)
+	atomic.StoreInt32(&r.count, int32(len(hooks)))
+}
+
+func (r *hookRegistry) enabled() bool {
+	return atomic.LoadInt32(&r.count) > 0
+}
+
+// fire calls hooks with call site resolved from caller of function that calls fire
+func (r *hookRegistry) fire(err *Error, skip int) {
+	if !r.enabled() {
+		return
+	}
+	r.fireAt(err, trace(skip+1))
+}
+
+// fireAt calls hooks with resolved call site. Call site is only formatted if any hook is registered
+func (r *hookRegistry) fireAt(err *Error, t TraceEntry) {
+	if !r.enabled() {
+		return
+	}
+
+	callSite := t.String()
+	hooks, _ := r.hooks.Load().([]hookEntry)
+	for _, e := range hooks {
+		e.hook(err, callSite)
+	}
+}
@@ -0,0 +1,123 @@
+package errx_test
+
+import (
+	"fmt"
+	"github.com/nbs-go/errx"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type hookCall struct {
+	code     string
+	callSite string
+}
+
+type hookRecorder struct {
+	mu    sync.Mutex
+	calls []hookCall
+}
+
+func (r *hookRecorder) hook(err *errx.Error, callSite string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, hookCall{code: err.Code(), callSite: callSite})
+}
+
+func TestOnNew(t *testing.T) {
+	r := new(hookRecorder)
+	remove := errx.OnNew(r.hook)
+
+	_ = errx.NewError("ERR_1", "Invalid input")
+	_ = errx.NewBuilder("myapp").NewError("ERR_2", "Invalid format")
+	remove()
+	_ = errx.NewError("ERR_3", "Not recorded")
+
+	if len(r.calls) != 2 {
+		t.Errorf("unexpected hook calls. Calls = %+v", r.calls)
+		return
+	}
+
+	if c := r.calls[0]; c.code != "ERR_1" || !strings.HasSuffix(c.callSite, "hooks_test.go:31") {
+		t.Errorf("unexpected NewError hook call. Call = %+v", c)
+	}
+
+	if c := r.calls[1]; c.code != "ERR_2" || !strings.HasSuffix(c.callSite, "hooks_test.go:32") {
+		t.Errorf("unexpected Builder.NewError hook call. Call = %+v", c)
+	}
+}
+
+func TestOnTrace(t *testing.T) {
+	r := new(hookRecorder)
+	remove := errx.OnTrace(r.hook)
+	defer remove()
+
+	_ = errx.NewError("ERR_1", "Invalid input").Trace()
+	_ = errx.Trace(fmt.Errorf("connection reset"))
+	_ = errx.Trace(nil)
+
+	if len(r.calls) != 2 {
+		t.Errorf("unexpected hook calls. Calls = %+v", r.calls)
+		return
+	}
+
+	if c := r.calls[0]; c.code != "ERR_1" || !strings.HasSuffix(c.callSite, "hooks_test.go:55") {
+		t.Errorf("unexpected Error.Trace hook call. Call = %+v", c)
+	}
+
+	if c := r.calls[1]; c.code != "ERROR" || !strings.HasSuffix(c.callSite, "hooks_test.go:56") {
+		t.Errorf("unexpected Trace hook call. Call = %+v", c)
+	}
+}
+
+func TestOnWrap(t *testing.T) {
+	r := new(hookRecorder)
+	remove := errx.OnWrap(r.hook)
+	defer remove()
+
+	// Tracing with source must not be reported as wrapping
+	_ = errx.NewError("ERR_1", "Invalid input").Trace(errx.Errorf("invalid email"))
+	_ = errx.NewError("ERR_2", "Invalid input").Wrap(fmt.Errorf("invalid email"))
+	_ = errx.Wrap(fmt.Errorf("connection reset"))
+	_ = errx.Wrap(nil)
+
+	if len(r.calls) != 2 {
+		t.Errorf("unexpected hook calls. Calls = %+v", r.calls)
+		return
+	}
+
+	if c := r.calls[0]; c.code != "ERR_2" || !strings.HasSuffix(c.callSite, "hooks_test.go:80") {
+		t.Errorf("unexpected Error.Wrap hook call. Call = %+v", c)
+	}
+
+	if c := r.calls[1]; c.code != "ERROR" || !strings.HasSuffix(c.callSite, "hooks_test.go:81") {
+		t.Errorf("unexpected Wrap hook call. Call = %+v", c)
+	}
+}
+
+func TestHooksConcurrent(t *testing.T) {
+	r := new(hookRecorder)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			remove := errx.OnTrace(r.hook)
+			remove()
+			remove()
+		}()
+		go func() {
+			defer wg.Done()
+			_ = errx.InternalError().Trace()
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkNewErrorWithoutHooks(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = errx.NewError("ERR_1", "Invalid input")
+	}
+}
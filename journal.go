@@ -0,0 +1,313 @@
+package errx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// OpenJournal opens or creates an append-only JSONL error journal. If the last line of existing journal is truncated,
+// e.g. process was killed while writing, then the partial line is removed
+func OpenJournal(path string, args ...JournalOptionFn) (*Journal, error) {
+	o := evaluateJournalOptions(args)
+
+	j := &Journal{
+		path:    path,
+		maxSize: o.maxSize,
+		maxAge:  o.maxAge,
+	}
+
+	if err := repairJournal(path); err != nil {
+		return nil, err
+	}
+
+	if err := j.open(); err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// Journal writes each recorded error as a JSON line to file. File is rotated when it exceeds max size or max age.
+// Rotated file is renamed with timestamp suffix, e.g. errors.jsonl.20060102T150405.000
+type Journal struct {
+	mu       sync.Mutex
+	path     string
+	maxSize  int64
+	maxAge   time.Duration
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// JournalEntry is a recorded error in journal
+type JournalEntry struct {
+	Time  time.Time `json:"time"`
+	Error *Error    `json:"error"`
+}
+
+// Record writes error to journal. If error is not *errx.Error then it will be wrapped into InternalError
+func (j *Journal) Record(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	xErr, ok := err.(*Error)
+	if !ok {
+		xErr = InternalError().wrap(err)
+	}
+
+	line, mErr := json.Marshal(JournalEntry{Time: time.Now().UTC(), Error: xErr})
+	if mErr != nil {
+		return mErr
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.shouldRotate(int64(len(line))) {
+		if rErr := j.rotate(); rErr != nil {
+			return rErr
+		}
+	}
+
+	n, wErr := j.file.Write(line)
+	j.size += int64(n)
+	return wErr
+}
+
+// Close closes journal file
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.file.Close()
+}
+
+func (j *Journal) open() error {
+	f, err := os.OpenFile(j.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	j.file = f
+	j.size = info.Size()
+	j.openedAt = time.Now()
+	if j.size > 0 {
+		// Existing journal is as old as its first entry, so age rotation survives restarts
+		j.openedAt = firstEntryTime(j.path, info.ModTime())
+	}
+	return nil
+}
+
+// firstEntryTime returns time of the first entry in journal. If it cannot be read, fallback is returned
+func firstEntryTime(path string, fallback time.Time) time.Time {
+	f, err := os.Open(path)
+	if err != nil {
+		return fallback
+	}
+	defer f.Close()
+
+	line, err := bufio.NewReader(f).ReadBytes('\n')
+	if err != nil {
+		return fallback
+	}
+
+	var entry struct {
+		Time time.Time `json:"time"`
+	}
+	if err = json.Unmarshal(line, &entry); err != nil || entry.Time.IsZero() {
+		return fallback
+	}
+	return entry.Time
+}
+
+// shouldRotate check if journal must be rotated before writing n bytes. Caller must hold lock
+func (j *Journal) shouldRotate(n int64) bool {
+	if j.size == 0 {
+		return false
+	}
+
+	if j.maxSize > 0 && j.size+n > j.maxSize {
+		return true
+	}
+
+	return j.maxAge > 0 && time.Since(j.openedAt) >= j.maxAge
+}
+
+// rotate renames current file and opens a new one. Caller must hold lock
+func (j *Journal) rotate() error {
+	if err := j.file.Close(); err != nil {
+		return err
+	}
+
+	rotated := fmt.Sprintf("%s.%s", j.path, time.Now().UTC().Format("20060102T150405.000"))
+
+	// Avoid overwriting journal that is rotated at the same time
+	for i := 1; fileExists(rotated); i++ {
+		rotated = fmt.Sprintf("%s.%s-%d", j.path, time.Now().UTC().Format("20060102T150405.000"), i)
+	}
+
+	if err := os.Rename(j.path, rotated); err != nil {
+		// Keep writing to current journal, so it is not left closed
+		if oErr := j.open(); oErr != nil {
+			return oErr
+		}
+		return err
+	}
+
+	return j.open()
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// repairJournal truncates partial last line of journal
+func repairJournal(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+
+	// Find the last new line from the end of file
+	buf := make([]byte, 4096)
+	end := info.Size()
+	for end > 0 {
+		start := end - int64(len(buf))
+		if start < 0 {
+			start = 0
+		}
+
+		chunk := buf[:end-start]
+		if _, err = f.ReadAt(chunk, start); err != nil && err != io.EOF {
+			return err
+		}
+
+		if i := bytes.LastIndexByte(chunk, '\n'); i >= 0 {
+			validSize := start + int64(i) + 1
+			if validSize == info.Size() {
+				return nil
+			}
+			return f.Truncate(validSize)
+		}
+		end = start
+	}
+
+	// No complete line is found
+	return f.Truncate(0)
+}
+
+// ReadJournal reads journal entries that match all filters. Truncated last line is ignored
+func ReadJournal(path string, filters ...JournalFilter) ([]JournalEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := make([]JournalEntry, 0)
+	r := bufio.NewReader(f)
+	for {
+		line, rErr := r.ReadBytes('\n')
+		if rErr == io.EOF {
+			// Line without new line at the end of file is a truncated line
+			return entries, nil
+		}
+		if rErr != nil {
+			return nil, rErr
+		}
+
+		var entry JournalEntry
+		if err = json.Unmarshal(line, &entry); err != nil {
+			return nil, err
+		}
+
+		if matchJournalFilters(entry, filters) {
+			entries = append(entries, entry)
+		}
+	}
+}
+
+// JournalFilter selects journal entries on reading
+type JournalFilter func(JournalEntry) bool
+
+// FilterNamespace selects entries with namespace
+func FilterNamespace(namespace string) JournalFilter {
+	return func(e JournalEntry) bool {
+		return e.Error.Namespace() == namespace
+	}
+}
+
+// FilterCode selects entries with code
+func FilterCode(code string) JournalFilter {
+	return func(e JournalEntry) bool {
+		return e.Error.Code() == code
+	}
+}
+
+// FilterSince selects entries recorded at or after t
+func FilterSince(t time.Time) JournalFilter {
+	return func(e JournalEntry) bool {
+		return !e.Time.Before(t)
+	}
+}
+
+func matchJournalFilters(e JournalEntry, filters []JournalFilter) bool {
+	for _, fn := range filters {
+		if !fn(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// MaxSize set maximum journal file size in bytes before rotated
+func MaxSize(size int64) JournalOptionFn {
+	return func(o *journalOptions) {
+		o.maxSize = size
+	}
+}
+
+// MaxAge set maximum duration journal file is written since opened before rotated
+func MaxAge(age time.Duration) JournalOptionFn {
+	return func(o *journalOptions) {
+		o.maxAge = age
+	}
+}
+
+type journalOptions struct {
+	maxSize int64
+	maxAge  time.Duration
+}
+
+type JournalOptionFn = func(*journalOptions)
+
+func evaluateJournalOptions(args []JournalOptionFn) *journalOptions {
+	o := new(journalOptions)
+	for _, fn := range args {
+		fn(o)
+	}
+	return o
+}
@@ -0,0 +1,189 @@
+package errx_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nbs-go/errx"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJournalRecordAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.jsonl")
+	j, err := errx.OpenJournal(path)
+	if err != nil {
+		t.Errorf("unexpected error on opening journal. Error = %s", err)
+		return
+	}
+
+	srcErr := errx.NewError("ERR_1", "customer.email is required").Trace(errx.Errorf("empty value"))
+	recorded := errx.NewError("ERR_2", "Failed to create customer", errx.WithNamespace("myapp"),
+		errx.AddMetadata("customerId", "c-1")).Trace(errx.Source(srcErr))
+
+	_ = j.Record(recorded)
+	_ = j.Record(fmt.Errorf("connection reset"))
+	_ = j.Record(nil)
+	_ = j.Close()
+
+	entries, err := errx.ReadJournal(path)
+	if err != nil {
+		t.Errorf("unexpected error on reading journal. Error = %s", err)
+		return
+	}
+
+	if len(entries) != 2 {
+		t.Errorf("unexpected entries length. Length = %d", len(entries))
+		return
+	}
+
+	loaded := entries[0].Error
+	if loaded.Error() != recorded.Error() {
+		t.Errorf("unexpected loaded error output.\n  Actual = %s\n  Expected = %s", loaded, recorded)
+	}
+
+	if !errors.Is(loaded, srcErr) {
+		t.Errorf("unexpected loaded error does not keep source")
+	}
+
+	if v, _ := loaded.Meta().GetString("customerId"); v != "c-1" {
+		t.Errorf("unexpected loaded metadata. Metadata = %+v", loaded.Metadata())
+	}
+
+	if entries[0].Time.IsZero() {
+		t.Errorf("unexpected empty time")
+	}
+
+	if e := entries[1].Error; !errors.Is(e, errx.InternalError()) || e.Unwrap().Error() != "connection reset" {
+		t.Errorf("unexpected loaded generic error. Error = %s", e)
+	}
+
+	// Filter entries
+	entries, _ = errx.ReadJournal(path, errx.FilterNamespace("myapp"), errx.FilterCode("ERR_2"),
+		errx.FilterSince(time.Now().Add(-time.Minute)))
+	if len(entries) != 1 {
+		t.Errorf("unexpected filtered entries length. Length = %d", len(entries))
+	}
+}
+
+func TestJournalRecoverTruncatedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.jsonl")
+	j, _ := errx.OpenJournal(path)
+	_ = j.Record(errx.NewError("ERR_1", "First"))
+	_ = j.Close()
+
+	// Simulate partial write
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	_, _ = f.WriteString(`{"time":"2022-01-01T00:00:00Z","error":{"code":"ERR_`)
+	_ = f.Close()
+
+	entries, err := errx.ReadJournal(path)
+	if err != nil || len(entries) != 1 {
+		t.Errorf("unexpected reading truncated journal. Error = %v, Length = %d", err, len(entries))
+	}
+
+	j, _ = errx.OpenJournal(path)
+	_ = j.Record(errx.NewError("ERR_2", "Second"))
+	_ = j.Close()
+
+	entries, err = errx.ReadJournal(path)
+	if err != nil || len(entries) != 2 || entries[1].Error.Code() != "ERR_2" {
+		t.Errorf("unexpected reading repaired journal. Error = %v, Entries = %+v", err, entries)
+	}
+}
+
+func TestJournalRotateBySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "errors.jsonl")
+	j, _ := errx.OpenJournal(path, errx.MaxSize(200))
+
+	for i := 0; i < 5; i++ {
+		_ = j.Record(errx.NewError(fmt.Sprintf("ERR_%d", i), "Rotated error"))
+	}
+	_ = j.Close()
+
+	files, _ := filepath.Glob(path + "*")
+	if len(files) < 2 {
+		t.Errorf("unexpected journal is not rotated. Files = %+v", files)
+	}
+
+	entries, _ := errx.ReadJournal(path)
+	if len(entries) == 0 || entries[len(entries)-1].Error.Code() != "ERR_4" {
+		t.Errorf("unexpected current journal entries. Entries = %+v", entries)
+	}
+}
+
+func TestJournalRotateByAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.jsonl")
+	j, _ := errx.OpenJournal(path, errx.MaxAge(10*time.Millisecond))
+
+	_ = j.Record(errx.NewError("ERR_1", "First"))
+	time.Sleep(20 * time.Millisecond)
+	_ = j.Record(errx.NewError("ERR_2", "Second"))
+	_ = j.Close()
+
+	entries, _ := errx.ReadJournal(path)
+	if len(entries) != 1 || entries[0].Error.Code() != "ERR_2" {
+		t.Errorf("unexpected journal is not rotated by age. Entries = %+v", entries)
+	}
+}
+
+func TestJournalRotateByAgeAfterReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.jsonl")
+	old := `{"time":"2022-01-01T00:00:00Z","error":{"code":"ERR_1","message":"First"}}` + "\n"
+	_ = os.WriteFile(path, []byte(old), 0644)
+
+	j, _ := errx.OpenJournal(path, errx.MaxAge(time.Hour))
+	_ = j.Record(errx.NewError("ERR_2", "Second"))
+	_ = j.Close()
+
+	entries, _ := errx.ReadJournal(path)
+	if len(entries) != 1 || entries[0].Error.Code() != "ERR_2" {
+		t.Errorf("unexpected reopened journal is not rotated by age. Entries = %+v", entries)
+	}
+}
+
+func TestJournalRecordGroupFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.jsonl")
+	j, _ := errx.OpenJournal(path)
+
+	notFoundErr := errx.NewError("ERR_1", "Resource not found")
+	g, _ := errx.NewGroup(context.Background())
+	g.GoNamed("loadInvoice", func() error {
+		return notFoundErr.Trace()
+	})
+	g.Go(func() error {
+		return errors.New("connection reset")
+	})
+	recorded := g.Wait()
+
+	_ = j.Record(recorded)
+	_ = j.Close()
+
+	entries, _ := errx.ReadJournal(path)
+	if len(entries) != 1 {
+		t.Errorf("unexpected entries length. Length = %d", len(entries))
+		return
+	}
+
+	var errs errx.Errors
+	loaded := entries[0].Error
+	if !errors.As(loaded, &errs) || len(errs) != 2 {
+		t.Errorf("unexpected aggregated causes are not restored. Error = %s", loaded)
+		return
+	}
+
+	if !errors.Is(loaded, notFoundErr) || len(errs[0].Traces()) != 1 {
+		t.Errorf("unexpected first cause. Error = %s", errs[0])
+	}
+
+	if task, _ := errs[0].Meta().GetString(errx.MetaTask); task != "loadInvoice" {
+		t.Errorf("unexpected task metadata of first cause. Metadata = %+v", errs[0].Metadata())
+	}
+
+	if loaded.Error() != recorded.Error() {
+		t.Errorf("unexpected loaded error output.\n  Actual = %s\n  Expected = %s", loaded, recorded)
+	}
+}
@@ -0,0 +1,161 @@
+package errx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// errorJSON is JSON representation of error chain
+type errorJSON struct {
+	Code      string                 `json:"code,omitempty"`
+	Namespace string                 `json:"namespace,omitempty"`
+	Message   string                 `json:"message"`
+	Type      string                 `json:"type,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	Traces    []TraceEntry           `json:"traces,omitempty"`
+	Panic     bool                   `json:"panic,omitempty"`
+	ExitCode  int                    `json:"exitCode,omitempty"`
+	Cause     *errorJSON             `json:"cause,omitempty"`
+	Causes    []*errorJSON           `json:"causes,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler interface. Error is serialized with its metadata, traces and causes
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newErrorJSON(e))
+}
+
+func newErrorJSON(err error) *errorJSON {
+	if err == nil {
+		return nil
+	}
+
+	// Aggregated errors, e.g. failures of Group, are serialized as list of causes
+	if errs, ok := err.(Errors); ok {
+		j := &errorJSON{
+			Message: ShortMessage(errs),
+			Type:    fmt.Sprintf("%T", err),
+			Causes:  make([]*errorJSON, len(errs)),
+		}
+		for i, xErr := range errs {
+			j.Causes[i] = newErrorJSON(xErr)
+		}
+		return j
+	}
+
+	xErr, ok := err.(*Error)
+	if !ok {
+		return &errorJSON{
+			Message: err.Error(),
+			Type:    fmt.Sprintf("%T", err),
+			Cause:   newErrorJSON(errors.Unwrap(err)),
+		}
+	}
+
+	j := &errorJSON{
+		Code:      xErr.code,
+		Namespace: xErr.namespace,
+		Message:   xErr.message,
+		Traces:    xErr.traces,
+		Panic:     xErr.panicked,
+		ExitCode:  xErr.exitCode,
+		Cause:     newErrorJSON(xErr.sourceErr),
+	}
+
+	if len(xErr.metadata) > 0 {
+		j.Metadata = xErr.metadata
+	}
+
+	return j
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface. Cause that is not *errx.Error is restored as a generic error
+// that keeps its message
+func (e *Error) UnmarshalJSON(b []byte) error {
+	var j errorJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+
+	*e = *j.toError()
+	return nil
+}
+
+func (j *errorJSON) toError() *Error {
+	e := &Error{
+		code:      j.Code,
+		namespace: j.Namespace,
+		message:   j.Message,
+		metadata:  j.Metadata,
+		traces:    j.Traces,
+		panicked:  j.Panic,
+		exitCode:  j.ExitCode,
+		sourceErr: j.Cause.toCause(),
+	}
+
+	if e.metadata == nil {
+		e.metadata = make(map[string]interface{})
+	}
+
+	if e.traces == nil {
+		e.traces = make([]TraceEntry, 0)
+	}
+
+	// Source traces are merged if they are the tail of current traces
+	if sErr, ok := e.sourceErr.(*Error); ok && len(sErr.traces) > 0 {
+		e.mergedSource = hasTracesSuffix(e.traces, sErr.traces)
+	}
+
+	return e
+}
+
+func (j *errorJSON) toCause() error {
+	if j == nil {
+		return nil
+	}
+
+	if j.Code != "" {
+		return j.toError()
+	}
+
+	if len(j.Causes) > 0 {
+		errs := make(Errors, len(j.Causes))
+		for i, c := range j.Causes {
+			errs[i] = c.toError()
+		}
+		return errs
+	}
+
+	return &decodedError{
+		message: j.Message,
+		cause:   j.Cause.toCause(),
+	}
+}
+
+// decodedError is a generic error restored from JSON
+type decodedError struct {
+	message string
+	cause   error
+}
+
+func (e *decodedError) Error() string {
+	return e.message
+}
+
+func (e *decodedError) Unwrap() error {
+	return e.cause
+}
+
+func hasTracesSuffix(traces, suffix []TraceEntry) bool {
+	if len(suffix) > len(traces) {
+		return false
+	}
+
+	offset := len(traces) - len(suffix)
+	for i, t := range suffix {
+		if !traces[offset+i].Equal(t) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package errx
+
+// Key is a typed metadata key. It prevents typo on metadata key and type assertion error on retrieving value
+//
+//	var UserID = errx.NewKey[int64]("userId")
+//
+//	err := ErrNotFound.Trace(UserID.Set(42))
+//	id, ok := UserID.Get(err)
+type Key[T any] struct {
+	name string
+}
+
+// NewKey creates a typed metadata key
+func NewKey[T any](name string) Key[T] {
+	return Key[T]{name: name}
+}
+
+// Name returns metadata key name
+func (k Key[T]) Name() string {
+	return k.name
+}
+
+// Set returns option to set metadata value. It can be used on NewError, Copy and Trace
+func (k Key[T]) Set(value T) SetOptionFn {
+	return AddMetadata(k.name, value)
+}
+
+// Get retrieve metadata value by walking error chain. It returns value from the first *errx.Error
+// that has the key with matching type
+func (k Key[T]) Get(err error) (T, bool) {
+	var result T
+	found := walk(err, func(err error) bool {
+		xErr, ok := err.(*Error)
+		if !ok {
+			return false
+		}
+
+		v, ok := xErr.Meta().Lookup(k.name)
+		if !ok {
+			return false
+		}
+
+		result, ok = v.(T)
+		return ok
+	})
+	return result, found
+}
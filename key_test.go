@@ -0,0 +1,54 @@
+package errx_test
+
+import (
+	"fmt"
+	"github.com/nbs-go/errx"
+	"testing"
+)
+
+var (
+	userIDKey   = errx.NewKey[int64]("userId")
+	usernameKey = errx.NewKey[string]("username")
+)
+
+func TestKeySetGet(t *testing.T) {
+	err := errx.NewError("ERR_1", "User not found", userIDKey.Set(42))
+
+	if v, ok := userIDKey.Get(err); !ok || v != 42 {
+		t.Errorf("unexpected userId. Value = %d", v)
+	}
+
+	if v, ok := err.Meta().Lookup(userIDKey.Name()); !ok || v != int64(42) {
+		t.Errorf("unexpected raw metadata value. Value = %v", v)
+	}
+
+	if _, ok := usernameKey.Get(err); ok {
+		t.Errorf("unexpected unset key is found")
+	}
+}
+
+func TestKeyGetFromChain(t *testing.T) {
+	srcErr := errx.NewError("ERR_1", "User not found", userIDKey.Set(42)).Trace()
+	err := fmt.Errorf("failed to load profile: %w",
+		errx.NewError("ERR_2", "Failed to load profile").Trace(errx.Source(srcErr), usernameKey.Set("john")))
+
+	if v, ok := userIDKey.Get(err); !ok || v != 42 {
+		t.Errorf("unexpected userId from chain. Value = %d", v)
+	}
+
+	if v, ok := usernameKey.Get(err); !ok || v != "john" {
+		t.Errorf("unexpected username from chain. Value = %s", v)
+	}
+}
+
+func TestKeyTypeMismatch(t *testing.T) {
+	err := errx.NewError("ERR_1", "User not found", errx.AddMetadata("userId", 42))
+
+	if _, ok := userIDKey.Get(err); ok {
+		t.Errorf("unexpected int value is retrieved by int64 key")
+	}
+
+	if _, ok := userIDKey.Get(nil); ok {
+		t.Errorf("unexpected value retrieved from nil error")
+	}
+}
@@ -0,0 +1,176 @@
+package errx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const defaultLogfmtPrefix = "err."
+
+// Logfmt renders error chain as a single logfmt line, e.g.
+//
+//	err.code=E_NOT_FOUND err.ns=myapp err.msg="Invoice not found" err.meta.invoiceId=42 err.cause="no rows"
+//	err.trace="app/invoice.go:42 (retry 2)|app/handler.go:10"
+//
+// Keys are printed in order of code, namespace, message, metadata sorted by key, cause and traces. If max length is
+// set with LogfmtMaxLength, the value that exceeds max length is truncated and the rest of fields are dropped
+func Logfmt(err error, args ...LogfmtOptionFn) string {
+	if err == nil {
+		return ""
+	}
+
+	o := evaluateLogfmtOptions(args)
+	l := &logfmtLine{prefix: o.prefix, maxLength: o.maxLength}
+
+	xErr, ok := err.(*Error)
+	if !ok {
+		l.add("msg", ShortMessage(err))
+		return l.String()
+	}
+
+	ok = l.add("code", xErr.code)
+	if ok && xErr.namespace != "" {
+		ok = l.add("ns", xErr.namespace)
+	}
+	ok = ok && l.add("msg", xErr.message)
+
+	keys := make([]string, 0, len(xErr.metadata))
+	for k := range xErr.metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		ok = ok && l.add("meta."+k, fmt.Sprintf("%v", xErr.metadata[k]))
+	}
+
+	if ok && xErr.sourceErr != nil {
+		ok = l.add("cause", ShortMessage(xErr.sourceErr))
+	}
+
+	if ok && len(xErr.traces) > 0 {
+		l.add("trace", logfmtTraces(xErr, o.traceArgs))
+	}
+
+	return l.String()
+}
+
+// logfmtTraces joins trace locations and their notes with "|". Elapsed time and source lines are not printed,
+// and "|" in notes is escaped, so trace can be split back into locations
+func logfmtTraces(e *Error, args []SetOptionFn) string {
+	c := *e.config(args...)
+	c.showElapsed = false
+	noSource := 0
+	c.sourceLines = &noSource
+
+	lines := c.traceLines(e.traces, true)
+	for i, line := range lines {
+		lines[i] = strings.ReplaceAll(line, "|", `\|`)
+	}
+	return strings.Join(lines, "|")
+}
+
+type logfmtLine struct {
+	sb        strings.Builder
+	prefix    string
+	maxLength int
+}
+
+// add appends key value pair. If max length is exceeded, value is truncated and add returns false,
+// so the rest of fields are dropped
+func (l *logfmtLine) add(key, value string) bool {
+	pair := l.prefix + logfmtKey(key) + "="
+	if l.sb.Len() > 0 {
+		pair = " " + pair
+	}
+
+	quoted := logfmtValue(value)
+	if l.maxLength <= 0 || l.sb.Len()+len(pair)+len(quoted) <= l.maxLength {
+		l.sb.WriteString(pair + quoted)
+		return true
+	}
+
+	// Truncate value at rune boundary to fit in max length
+	available := l.maxLength - l.sb.Len() - len(pair)
+	n := len(value) - 1
+	if n > available {
+		n = available
+	}
+	for ; n >= 0; n-- {
+		if !utf8.RuneStart(value[n]) {
+			continue
+		}
+		if quoted = logfmtValue(value[:n] + "..."); len(quoted) <= available {
+			l.sb.WriteString(pair + quoted)
+			break
+		}
+	}
+	return false
+}
+
+func (l *logfmtLine) String() string {
+	return l.sb.String()
+}
+
+// logfmtKey replaces characters that are not allowed in logfmt key with underscore
+func logfmtKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// logfmtValue quotes value if it is empty, or contains space, equal sign, quote or non-printable characters
+func logfmtValue(value string) string {
+	if value == "" {
+		return `""`
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return strconv.Quote(value)
+		}
+	}
+	return value
+}
+
+// LogfmtPrefix set key prefix. Default prefix is "err."
+func LogfmtPrefix(prefix string) LogfmtOptionFn {
+	return func(o *logfmtOptions) {
+		o.prefix = prefix
+	}
+}
+
+// LogfmtMaxLength set maximum length of line in bytes
+func LogfmtMaxLength(n int) LogfmtOptionFn {
+	return func(o *logfmtOptions) {
+		o.maxLength = n
+	}
+}
+
+// LogfmtTraces set options to render traces, e.g. LogfmtTraces(TracePath(ModuleRelative))
+func LogfmtTraces(args ...SetOptionFn) LogfmtOptionFn {
+	return func(o *logfmtOptions) {
+		o.traceArgs = append(o.traceArgs, args...)
+	}
+}
+
+type logfmtOptions struct {
+	prefix    string
+	maxLength int
+	traceArgs []SetOptionFn
+}
+
+type LogfmtOptionFn = func(*logfmtOptions)
+
+func evaluateLogfmtOptions(args []LogfmtOptionFn) *logfmtOptions {
+	o := &logfmtOptions{prefix: defaultLogfmtPrefix}
+	for _, fn := range args {
+		fn(o)
+	}
+	return o
+}
@@ -0,0 +1,80 @@
+package errx_test
+
+import (
+	"context"
+	"errors"
+	"github.com/nbs-go/errx"
+	"strings"
+	"testing"
+)
+
+func TestLogfmt(t *testing.T) {
+	b := errx.NewBuilder("myapp")
+	notFound := b.NewError("E_NOT_FOUND", "Invoice not found")
+
+	err := notFound.Trace(errx.Source(errx.Trace(errors.New(`no "rows"`))), errx.AddMetadata("userId", 42),
+		errx.AddMetadata("invoice no", "INV 1"))
+
+	expected := `err.code=E_NOT_FOUND err.ns=myapp err.msg="Invoice not found" err.meta.invoice_no="INV 1" ` +
+		`err.meta.userId=42 err.cause="Internal Error: no \"rows\"" err.trace=logfmt_test.go:15|logfmt_test.go:15`
+	if actual := errx.Logfmt(err, errx.LogfmtTraces(errx.TracePath(errx.BaseName))); actual != expected {
+		t.Errorf("unexpected logfmt. Actual = %s", actual)
+	}
+
+	if actual := errx.Logfmt(errors.New("timeout"), errx.LogfmtPrefix("error_")); actual != "error_msg=timeout" {
+		t.Errorf("unexpected logfmt of generic error. Actual = %s", actual)
+	}
+
+	if actual := errx.Logfmt(nil); actual != "" {
+		t.Errorf("unexpected logfmt of nil error. Actual = %s", actual)
+	}
+}
+
+func TestLogfmtMaxLength(t *testing.T) {
+	err := errx.NewError("ERR_1", "Bad Request", errx.AddMetadata("reason", "email is already registered"))
+
+	testCases := []struct {
+		maxLength int
+		expected  string
+	}{
+		{100, `err.code=ERR_1 err.msg="Bad Request" err.meta.reason="email is already registered"`},
+		{56, `err.code=ERR_1 err.msg="Bad Request" err.meta.reason=...`},
+		{60, `err.code=ERR_1 err.msg="Bad Request" err.meta.reason=emai...`},
+		{30, `err.code=ERR_1 err.msg=Bad...`},
+		{16, `err.code=ERR_1`},
+	}
+
+	for _, tc := range testCases {
+		actual := errx.Logfmt(err, errx.LogfmtMaxLength(tc.maxLength))
+		if actual != tc.expected || len(actual) > tc.maxLength {
+			t.Errorf("unexpected truncated logfmt. MaxLength = %d, Actual = %s", tc.maxLength, actual)
+		}
+	}
+}
+
+func TestLogfmtTraceNotes(t *testing.T) {
+	err := errx.NewError("ERR_1", "Bad Request").Trace(errx.WithNote("retry 1|2"))
+
+	expected := `err.code=ERR_1 err.msg="Bad Request" err.trace="logfmt_test.go:56 (retry 1\\|2)"`
+	if actual := errx.Logfmt(err, errx.LogfmtTraces(errx.TracePath(errx.BaseName))); actual != expected {
+		t.Errorf("unexpected logfmt trace notes. Actual = %s", actual)
+	}
+}
+
+func TestLogfmtGroupFailure(t *testing.T) {
+	g, _ := errx.NewGroup(context.Background())
+	g.Go(func() error {
+		return errx.NewError("ERR_1", "Resource not found").Trace()
+	})
+	g.Go(func() error {
+		return errors.New("connection reset")
+	})
+
+	err := errx.NewError("ERR_9", "Failed to sync").Trace(errx.Source(g.Wait()))
+
+	expected := `err.code=ERR_9 err.msg="Failed to sync" ` +
+		`err.cause="errx: [ERR_2] One or more tasks failed: Resource not found; Internal Error: connection reset"`
+	if actual := errx.Logfmt(err); !strings.HasPrefix(actual, expected+" err.trace=") {
+		t.Errorf("unexpected logfmt of group failure. Actual = %s", actual)
+	}
+}
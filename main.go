@@ -0,0 +1,131 @@
+package errx
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Exit codes follow sysexits.h convention
+const (
+	ExitOK          = 0
+	ExitFailure     = 1
+	ExitUsage       = 64
+	ExitDataErr     = 65
+	ExitNoInput     = 66
+	ExitUnavailable = 69
+	ExitSoftware    = 70
+	ExitCantCreate  = 73
+	ExitIOErr       = 74
+	ExitTempFail    = 75
+	ExitNoPerm      = 77
+	ExitConfig      = 78
+)
+
+// VerboseEnv is environment variable to print full error with traces on Main
+const VerboseEnv = "ERRX_VERBOSE"
+
+// Main runs fn as command-line program main function. If fn returns error or panics, error is printed to stderr
+// and process exits with code resolved by ExitCode. By default, only error messages are printed. Full error with
+// traces is printed if --verbose flag is set in arguments or ERRX_VERBOSE environment variable is set to true.
+// The --verbose flag is removed from os.Args before fn is called, so it does not conflict with flag parsing in fn
+func Main(fn func() error) {
+	verbose, args := isVerbose(os.Args[1:], os.Getenv(VerboseEnv))
+	os.Args = append(os.Args[:1:1], args...)
+	if code := run(fn, os.Stderr, verbose); code != ExitOK {
+		os.Exit(code)
+	}
+}
+
+func run(fn func() error, w io.Writer, verbose bool) (code int) {
+	var err error
+	func() {
+		defer Recover(&err)
+		err = fn()
+	}()
+
+	if err == nil {
+		return ExitOK
+	}
+
+	if verbose {
+		_, _ = fmt.Fprintln(w, err.Error())
+	} else {
+		_, _ = fmt.Fprintln(w, ShortMessage(err))
+	}
+
+	return ExitCode(err)
+}
+
+// isVerbose check if verbose flag is set in arguments or environment variable. It returns arguments without
+// verbose flag. Arguments after "--" are not checked
+func isVerbose(args []string, env string) (bool, []string) {
+	verbose := false
+	rest := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		if arg == "--verbose" || arg == "-verbose" {
+			verbose = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+
+	switch strings.ToLower(env) {
+	case "1", "true", "yes":
+		verbose = true
+	}
+	return verbose, rest
+}
+
+// ShortMessage returns messages of error chain in a single line without traces, as printed by SingleLineFormatter
+func ShortMessage(err error) string {
+	return SingleLineFormatter{}.message(err)
+}
+
+// ExitCode resolves process exit code of error. It returns exit code of the first *errx.Error in chain that has one.
+// Otherwise, common error classes are mapped to sysexits.h values and other errors exit with ExitFailure
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	code := 0
+	walk(err, func(err error) bool {
+		if xErr, ok := err.(*Error); ok && xErr.exitCode != 0 {
+			code = xErr.exitCode
+			return true
+		}
+		return false
+	})
+	if code != 0 {
+		return code
+	}
+
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return ExitUsage
+	case errors.Is(err, os.ErrNotExist):
+		return ExitNoInput
+	case errors.Is(err, os.ErrPermission):
+		return ExitNoPerm
+	case errors.Is(err, context.DeadlineExceeded):
+		return ExitTempFail
+	case errors.Is(err, InternalError()), errors.Is(err, PanicError()):
+		return ExitSoftware
+	}
+
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return ExitIOErr
+	}
+
+	return ExitFailure
+}
@@ -0,0 +1,151 @@
+package errx_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/nbs-go/errx"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	b := errx.NewBuilder("mycli", errx.WithExitCode(errx.ExitDataErr))
+	invalidErr := b.NewError("ERR_1", "Invalid input")
+	configErr := b.NewError("ERR_2", "Missing config", errx.WithExitCode(errx.ExitConfig))
+
+	testCases := []struct {
+		err      error
+		expected int
+	}{
+		{nil, errx.ExitOK},
+		{invalidErr.Trace(), errx.ExitDataErr},
+		{configErr.Trace(), errx.ExitConfig},
+		{b.Get("UNKNOWN"), errx.ExitDataErr},
+		{fmt.Errorf("load: %w", configErr), errx.ExitConfig},
+		{errx.NewError("ERR_3", "Unavailable", errx.WithExitCode(errx.ExitUnavailable)).Copy(), errx.ExitUnavailable},
+		{errx.Trace(os.ErrNotExist), errx.ExitNoInput},
+		{errx.Trace(os.ErrPermission), errx.ExitNoPerm},
+		{context.DeadlineExceeded, errx.ExitTempFail},
+		{errx.InternalError(), errx.ExitSoftware},
+		{&os.PathError{Op: "write", Path: "/tmp/out", Err: errors.New("disk failure")}, errx.ExitIOErr},
+		{fmt.Errorf("unknown failure"), errx.ExitFailure},
+	}
+
+	for i, tc := range testCases {
+		if code := errx.ExitCode(tc.err); code != tc.expected {
+			t.Errorf("unexpected exit code. Index = %d, Code = %d, Expected = %d", i, code, tc.expected)
+		}
+	}
+
+	if code := b.ExitCode(); code != errx.ExitDataErr {
+		t.Errorf("unexpected builder exit code. Code = %d", code)
+	}
+}
+
+func TestShortMessage(t *testing.T) {
+	srcErr := errx.NewError("ERR_1", "customer.email is required").Trace(errx.Errorf("empty value"))
+	err := errx.NewError("ERR_2", "Failed to create customer", errx.WithNamespace("mycli")).Trace(errx.Source(srcErr))
+
+	expected := "mycli: [ERR_2] Failed to create customer: customer.email is required: empty value"
+	if msg := errx.ShortMessage(err); msg != expected {
+		t.Errorf("unexpected short message. Message = %s", msg)
+	}
+}
+
+func TestShortMessageGroup(t *testing.T) {
+	g, _ := errx.NewGroup(context.Background())
+	g.Go(func() error {
+		return errx.NewError("ERR_1", "Resource not found", errx.WithNamespace("mycli")).Trace()
+	})
+	g.Go(func() error {
+		return fmt.Errorf("connection reset")
+	})
+
+	expected := "errx: [ERR_2] One or more tasks failed: mycli: [ERR_1] Resource not found; " +
+		"Internal Error: connection reset"
+	if msg := errx.ShortMessage(g.Wait()); msg != expected {
+		t.Errorf("unexpected short message of group failure. Message = %s", msg)
+	}
+}
+
+func TestMainHelperProcess(t *testing.T) {
+	switch os.Getenv("ERRX_TEST_MAIN") {
+	case "ok":
+		errx.Main(func() error {
+			return nil
+		})
+	case "error":
+		errx.Main(func() error {
+			return errx.NewError("ERR_1", "Missing config", errx.WithNamespace("mycli"),
+				errx.WithExitCode(errx.ExitConfig)).Trace()
+		})
+	case "panic":
+		errx.Main(func() error {
+			panic("unexpected state")
+		})
+	case "flag":
+		os.Args = []string{"mycli", "--verbose", "-config", "app.yml"}
+		errx.Main(func() error {
+			fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+			config := fs.String("config", "", "config file")
+			if err := fs.Parse(os.Args[1:]); err != nil {
+				return err
+			}
+			return errx.NewError("ERR_1", "Missing config "+*config, errx.WithExitCode(errx.ExitConfig)).Trace()
+		})
+	}
+}
+
+func runMainHelper(t *testing.T, scenario string, env ...string) (int, string) {
+	cmd := exec.Command(os.Args[0], "-test.run=TestMainHelperProcess")
+	cmd.Env = append(os.Environ(), "ERRX_TEST_MAIN="+scenario)
+	cmd.Env = append(cmd.Env, env...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), stderr.String()
+	}
+
+	if err != nil {
+		t.Fatalf("unexpected error on running helper process. Error = %s", err)
+	}
+
+	return 0, stderr.String()
+}
+
+func TestMainExit(t *testing.T) {
+	if code, _ := runMainHelper(t, "ok"); code != errx.ExitOK {
+		t.Errorf("unexpected exit code on success. Code = %d", code)
+	}
+
+	code, stderr := runMainHelper(t, "error")
+	if code != errx.ExitConfig || stderr != "mycli: [ERR_1] Missing config\n" {
+		t.Errorf("unexpected short output. Code = %d, Stderr = %s", code, stderr)
+	}
+
+	code, stderr = runMainHelper(t, "error", errx.VerboseEnv+"=true")
+	if code != errx.ExitConfig || !strings.Contains(stderr, "Traces => ") {
+		t.Errorf("unexpected verbose output. Code = %d, Stderr = %s", code, stderr)
+	}
+
+	// Verbose flag is removed before flags are parsed
+	code, stderr = runMainHelper(t, "flag")
+	if code != errx.ExitConfig || !strings.Contains(stderr, "Missing config app.yml\n  Traces => ") {
+		t.Errorf("unexpected output with verbose flag. Code = %d, Stderr = %s", code, stderr)
+	}
+
+	code, stderr = runMainHelper(t, "panic")
+	if code != errx.ExitSoftware || stderr != "Recovered from panic: unexpected state\n" {
+		t.Errorf("unexpected panic output. Code = %d, Stderr = %s", code, stderr)
+	}
+}
@@ -0,0 +1,202 @@
+package errx
+
+import (
+	"math"
+	"reflect"
+	"sort"
+)
+
+// MergePolicy defines how metadata collision is resolved when merging metadata on Trace
+type MergePolicy int
+
+const (
+	// MergeOverride replaces existing value with the new one. This is the default policy
+	MergeOverride MergePolicy = iota
+	// MergeKeep keeps existing value and discards the new one
+	MergeKeep
+	// MergeError keeps existing value if a key already exists with different value, and records the collision
+	// as MetadataCollisionError that can be retrieved with Collisions. It is intended to surface programming
+	// mistake early, e.g. in development or tests
+	MergeError
+)
+
+// MetaCollisionKey is metadata key of MetadataCollisionError that contains the collided metadata key
+const MetaCollisionKey = "key"
+
+// MetadataView is a read-only view of error metadata. Nested maps and slices are copied on retrieval,
+// so the error metadata can not be modified through the view
+type MetadataView struct {
+	m map[string]interface{}
+}
+
+// Lookup retrieve metadata value by key
+func (v MetadataView) Lookup(key string) (interface{}, bool) {
+	val, ok := v.m[key]
+	if !ok {
+		return nil, false
+	}
+	return deepCopyValue(val), true
+}
+
+// GetString retrieve metadata value by key. Returns false if key is not found or value is not a string
+func (v MetadataView) GetString(key string) (string, bool) {
+	val, ok := v.m[key].(string)
+	return val, ok
+}
+
+// GetInt retrieve metadata value by key. Any integer type and integral float value that fits in int is converted.
+// Returns false if key is not found or value is not convertible to int
+func (v MetadataView) GetInt(key string) (int, bool) {
+	val, ok := v.m[key]
+	if !ok || val == nil {
+		return 0, false
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := rv.Int()
+		if n < math.MinInt || n > math.MaxInt {
+			return 0, false
+		}
+		return int(n), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := rv.Uint()
+		if n > math.MaxInt {
+			return 0, false
+		}
+		return int(n), true
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f != math.Trunc(f) || f < math.MinInt || f > math.MaxInt {
+			return 0, false
+		}
+		return int(f), true
+	}
+
+	return 0, false
+}
+
+// Has check if metadata key is exists
+func (v MetadataView) Has(key string) bool {
+	_, ok := v.m[key]
+	return ok
+}
+
+// Len returns number of metadata
+func (v MetadataView) Len() int {
+	return len(v.m)
+}
+
+// Keys returns sorted metadata keys
+func (v MetadataView) Keys() []string {
+	keys := make([]string, 0, len(v.m))
+	for k := range v.m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ToMap returns a deep copy of metadata
+func (v MetadataView) ToMap() map[string]interface{} {
+	return copyMetadata(v.m)
+}
+
+// mergeMetadata merge src into dst with policy. It returns sorted keys that collide with different value
+// if policy is MergeError
+func mergeMetadata(dst, src map[string]interface{}, policy MergePolicy) []string {
+	collisions := make([]string, 0)
+	for k, v := range src {
+		existing, ok := dst[k]
+		if ok {
+			switch policy {
+			case MergeKeep:
+				continue
+			case MergeError:
+				if !reflect.DeepEqual(existing, v) {
+					collisions = append(collisions, k)
+					continue
+				}
+			}
+		}
+		dst[k] = v
+	}
+	sort.Strings(collisions)
+	return collisions
+}
+
+// collisionErrors returns MetadataCollisionError for each collided key, traced at t
+func collisionErrors(keys []string, t TraceEntry) Errors {
+	errs := make(Errors, len(keys))
+	for i, k := range keys {
+		errs[i] = MetadataCollisionError.AddMetadata(MetaCollisionKey, k)
+		errs[i].traces = []TraceEntry{t}
+	}
+	return errs
+}
+
+// deepCopyValue copy maps and slices recursively. Other values are returned as is
+func deepCopyValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		return copyMetadata(val)
+	case []interface{}:
+		cp := make([]interface{}, len(val))
+		for i, item := range val {
+			cp[i] = deepCopyValue(item)
+		}
+		return cp
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.IsNil() {
+			return v
+		}
+		cp := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			cp.SetMapIndex(iter.Key(), deepCopyReflect(iter.Value()))
+		}
+		return cp.Interface()
+	case reflect.Slice:
+		if rv.IsNil() {
+			return v
+		}
+		cp := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			cp.Index(i).Set(deepCopyReflect(rv.Index(i)))
+		}
+		return cp.Interface()
+	}
+
+	return v
+}
+
+func deepCopyReflect(rv reflect.Value) reflect.Value {
+	if !rv.IsValid() || !rv.CanInterface() {
+		return rv
+	}
+
+	// Interface value may hold nil, keep it as is
+	if rv.Kind() == reflect.Interface && rv.IsNil() {
+		return rv
+	}
+
+	cp := reflect.ValueOf(deepCopyValue(rv.Interface()))
+	if !cp.IsValid() {
+		return rv
+	}
+
+	if cp.Type() != rv.Type() {
+		// Value is stored in interface typed container, e.g. map[string]interface{}
+		ptr := reflect.New(rv.Type()).Elem()
+		ptr.Set(cp)
+		return ptr
+	}
+	return cp
+}
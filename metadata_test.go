@@ -0,0 +1,152 @@
+package errx_test
+
+import (
+	"errors"
+	"github.com/nbs-go/errx"
+	"testing"
+)
+
+func TestMetadataGetterReturnsCopy(t *testing.T) {
+	sentinelErr := errx.NewError("ERR_1", "Resource not found", errx.AddMetadata("httpStatus", 404))
+
+	meta := sentinelErr.Metadata()
+	meta["httpStatus"] = 500
+
+	if v, _ := sentinelErr.Meta().GetInt("httpStatus"); v != 404 {
+		t.Errorf("unexpected sentinel metadata is modified. httpStatus = %d", v)
+	}
+}
+
+func TestWithMetadataDoesNotShareInput(t *testing.T) {
+	input := map[string]interface{}{
+		"httpStatus": 400,
+	}
+
+	err := errx.NewError("ERR_1", "Invalid input", errx.WithMetadata(input), errx.AddMetadata("field", "email"))
+
+	if len(input) != 1 {
+		t.Errorf("unexpected input map is modified by AddMetadata. Input = %+v", input)
+	}
+
+	input["httpStatus"] = 500
+	if v, _ := err.Meta().GetInt("httpStatus"); v != 400 {
+		t.Errorf("unexpected metadata is modified through input map. httpStatus = %d", v)
+	}
+}
+
+func TestCopyDeepCopiesMetadata(t *testing.T) {
+	err := errx.NewError("ERR_1", "Invalid input", errx.AddMetadata("fields", map[string]interface{}{
+		"email": []interface{}{"required"},
+	}), errx.AddMetadata("tags", []string{"input"}))
+
+	cpErr := err.Copy()
+
+	fields, _ := cpErr.Meta().Lookup("fields")
+	fields.(map[string]interface{})["email"].([]interface{})[0] = "invalid"
+
+	tags, _ := cpErr.Meta().Lookup("tags")
+	tags.([]string)[0] = "modified"
+
+	meta := err.Metadata()
+	if v := meta["fields"].(map[string]interface{})["email"].([]interface{})[0]; v != "required" {
+		t.Errorf("unexpected nested map is shared. Value = %s", v)
+	}
+
+	if v := meta["tags"].([]string)[0]; v != "input" {
+		t.Errorf("unexpected nested slice is shared. Value = %s", v)
+	}
+}
+
+func TestMetadataViewGetters(t *testing.T) {
+	err := errx.NewError("ERR_1", "Invalid input", errx.WithMetadata(map[string]interface{}{
+		"userId":   int64(42),
+		"retry":    float64(3),
+		"ratio":    0.5,
+		"username": "john",
+	}))
+	meta := err.Meta()
+
+	if v, ok := meta.GetInt("userId"); !ok || v != 42 {
+		t.Errorf("unexpected userId. Value = %d", v)
+	}
+
+	if v, ok := meta.GetInt("retry"); !ok || v != 3 {
+		t.Errorf("unexpected retry. Value = %d", v)
+	}
+
+	if _, ok := meta.GetInt("ratio"); ok {
+		t.Errorf("unexpected non integral float is converted to int")
+	}
+
+	if v, ok := meta.GetString("username"); !ok || v != "john" {
+		t.Errorf("unexpected username. Value = %s", v)
+	}
+
+	if _, ok := meta.GetString("userId"); ok {
+		t.Errorf("unexpected int is retrieved as string")
+	}
+
+	if _, ok := meta.Lookup("unknown"); ok {
+		t.Errorf("unexpected unknown key is found")
+	}
+
+	if keys := meta.Keys(); len(keys) != 4 || keys[0] != "ratio" {
+		t.Errorf("unexpected keys. Keys = %+v", keys)
+	}
+}
+
+func TestTraceMergePolicy(t *testing.T) {
+	err := errx.NewError("ERR_1", "Invalid input", errx.AddMetadata("field", "email"))
+
+	overridden := err.Trace(errx.AddMetadata("field", "phone"))
+	if v, _ := overridden.Meta().GetString("field"); v != "phone" {
+		t.Errorf("unexpected override policy result. Value = %s", v)
+	}
+
+	kept := err.Trace(errx.AddMetadata("field", "phone"), errx.WithMergePolicy(errx.MergeKeep))
+	if v, _ := kept.Meta().GetString("field"); v != "email" {
+		t.Errorf("unexpected keep policy result. Value = %s", v)
+	}
+
+	// Same value is not a collision
+	_ = err.Trace(errx.AddMetadata("field", "email"), errx.WithMergePolicy(errx.MergeError))
+}
+
+func TestTraceMergePolicyError(t *testing.T) {
+	err := errx.NewError("ERR_1", "Invalid input", errx.AddMetadata("field", "email"))
+
+	tErr := err.Trace(errx.AddMetadata("field", "phone"), errx.AddMetadata("form", "signup"),
+		errx.WithNote("while validating"), errx.WithMergePolicy(errx.MergeError))
+
+	if v, _ := tErr.Meta().GetString("field"); v != "email" {
+		t.Errorf("unexpected collided value is overridden. Value = %s", v)
+	}
+
+	if v, _ := tErr.Meta().GetString("form"); v != "signup" {
+		t.Errorf("unexpected new metadata is not merged. Value = %s", v)
+	}
+
+	if note := tErr.TraceEntries()[0].Note; note != "while validating" {
+		t.Errorf("unexpected collision is written to note. Note = %s", note)
+	}
+
+	collisions := tErr.Collisions()
+	if len(collisions) != 1 || !errors.Is(collisions, errx.MetadataCollisionError) {
+		t.Errorf("unexpected collisions. Collisions = %v", collisions)
+		return
+	}
+
+	if k, _ := collisions[0].Meta().GetString(errx.MetaCollisionKey); k != "field" {
+		t.Errorf("unexpected collided key. Key = %s", k)
+	}
+
+	// Collisions are kept when error is traced again
+	if c := tErr.Trace().Collisions(); len(c) != 1 {
+		t.Errorf("unexpected collisions after trace. Collisions = %v", c)
+	}
+
+	sameErr := err.Trace(errx.AddMetadata("field", "email"), errx.WithMergePolicy(errx.MergeError))
+	if c := sameErr.Collisions(); len(c) != 0 {
+		t.Errorf("unexpected collisions on same value. Collisions = %v", c)
+	}
+}
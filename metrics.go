@@ -0,0 +1,295 @@
+package errx
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// OtherLabel replaces namespace, code and severity label that is not registered in Metrics
+	OtherLabel = "other"
+	// MetaSeverity is metadata key that is read by default severity function
+	MetaSeverity = "severity"
+
+	rateWindow = 60
+)
+
+// Severity labels that are allowed by default
+const (
+	SeverityDebug    = "debug"
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityError    = "error"
+	SeverityCritical = "critical"
+)
+
+// NewMetrics creates in-process error metrics recorder
+func NewMetrics(args ...MetricsOptionFn) *Metrics {
+	o := evaluateMetricsOptions(args)
+	return &Metrics{
+		series:     make(map[seriesKey]*series),
+		codes:      make(map[string]map[string]struct{}),
+		severity:   o.severity,
+		severities: o.severities,
+	}
+}
+
+// Metrics counts errors by namespace, code and severity. It exposes counters and rates through expvar
+// and Prometheus text format. Once a Builder is registered, codes that are not registered in any Builder
+// are recorded as OtherLabel, so label cardinality is bounded. Severity that is not allowed is recorded as OtherLabel
+type Metrics struct {
+	mu         sync.Mutex
+	series     map[seriesKey]*series
+	codes      map[string]map[string]struct{}
+	severity   func(*Error) string
+	severities map[string]struct{}
+}
+
+type seriesKey struct {
+	namespace string
+	code      string
+	severity  string
+}
+
+type series struct {
+	count      uint64
+	buckets    [rateWindow]uint64
+	bucketSecs [rateWindow]int64
+}
+
+// MetricSample is a snapshot of a single error series
+type MetricSample struct {
+	Namespace string  `json:"namespace"`
+	Code      string  `json:"code"`
+	Severity  string  `json:"severity"`
+	Count     uint64  `json:"count"`
+	Rate      float64 `json:"rate1m"`
+}
+
+// Register registers codes of builder as allowed label values
+func (m *Metrics) Register(b *Builder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	codes, ok := m.codes[b.Namespace()]
+	if !ok {
+		codes = make(map[string]struct{})
+		m.codes[b.Namespace()] = codes
+	}
+
+	for _, c := range b.Codes() {
+		codes[c] = struct{}{}
+	}
+}
+
+// Record count error. If error chain does not contain *errx.Error, it is counted as InternalError
+func (m *Metrics) Record(err error) {
+	if err == nil {
+		return
+	}
+
+	var xErr *Error
+	if !errors.As(err, &xErr) {
+		xErr = InternalError()
+	}
+
+	severity := m.severity(xErr)
+	if _, ok := m.severities[severity]; !ok {
+		severity = OtherLabel
+	}
+	now := time.Now().Unix()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	k := m.labels(xErr, severity)
+	s, ok := m.series[k]
+	if !ok {
+		s = new(series)
+		m.series[k] = s
+	}
+
+	s.count++
+	i := now % rateWindow
+	if s.bucketSecs[i] != now {
+		s.bucketSecs[i] = now
+		s.buckets[i] = 0
+	}
+	s.buckets[i]++
+}
+
+// labels resolves series labels of error. Caller must hold lock
+func (m *Metrics) labels(err *Error, severity string) seriesKey {
+	k := seriesKey{
+		namespace: err.Namespace(),
+		code:      err.Code(),
+		severity:  severity,
+	}
+
+	// If no builder is registered, then labels are not bounded
+	if len(m.codes) == 0 {
+		return k
+	}
+
+	codes, ok := m.codes[k.namespace]
+	if !ok {
+		k.namespace = OtherLabel
+		k.code = OtherLabel
+		return k
+	}
+
+	if _, ok = codes[k.code]; !ok {
+		k.code = OtherLabel
+	}
+
+	return k
+}
+
+// Snapshot returns samples of all series sorted by namespace, code and severity
+func (m *Metrics) Snapshot() []MetricSample {
+	now := time.Now().Unix()
+
+	m.mu.Lock()
+	samples := make([]MetricSample, 0, len(m.series))
+	for k, s := range m.series {
+		var n uint64
+		for i, secs := range s.bucketSecs {
+			if now-secs < rateWindow {
+				n += s.buckets[i]
+			}
+		}
+
+		samples = append(samples, MetricSample{
+			Namespace: k.namespace,
+			Code:      k.code,
+			Severity:  k.severity,
+			Count:     s.count,
+			Rate:      float64(n) / rateWindow,
+		})
+	}
+	m.mu.Unlock()
+
+	sort.Slice(samples, func(i, j int) bool {
+		a, b := samples[i], samples[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		return a.Severity < b.Severity
+	})
+
+	return samples
+}
+
+// String implements expvar.Var interface. It returns samples in JSON
+func (m *Metrics) String() string {
+	b, _ := json.Marshal(m.Snapshot())
+	return string(b)
+}
+
+// Publish publishes metrics to expvar with name. Same as expvar.Publish, it panics if name is already registered
+func (m *Metrics) Publish(name string) {
+	expvar.Publish(name, m)
+}
+
+// Handler returns http.Handler that serves metrics in Prometheus text exposition format
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = w.Write([]byte(m.prometheusText()))
+	})
+}
+
+func (m *Metrics) prometheusText() string {
+	samples := m.Snapshot()
+
+	var sb strings.Builder
+	sb.WriteString("# HELP errx_errors_total Total number of errors by namespace, code and severity.\n")
+	sb.WriteString("# TYPE errx_errors_total counter\n")
+	for _, s := range samples {
+		_, _ = fmt.Fprintf(&sb, "errx_errors_total{%s} %d\n", s.promLabels(), s.Count)
+	}
+
+	sb.WriteString("# HELP errx_errors_rate1m Average errors per second in the last minute.\n")
+	sb.WriteString("# TYPE errx_errors_rate1m gauge\n")
+	for _, s := range samples {
+		_, _ = fmt.Fprintf(&sb, "errx_errors_rate1m{%s} %g\n", s.promLabels(), s.Rate)
+	}
+
+	return sb.String()
+}
+
+var promLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func (s MetricSample) promLabels() string {
+	return fmt.Sprintf(`namespace="%s",code="%s",severity="%s"`,
+		promLabelReplacer.Replace(s.Namespace),
+		promLabelReplacer.Replace(s.Code),
+		promLabelReplacer.Replace(s.Severity))
+}
+
+// DefaultSeverity resolves severity from MetaSeverity metadata. If it is not set, then error recovered from panic
+// is SeverityCritical and others are SeverityError. Metadata value is free-form, so Metrics records value that is
+// not allowed by SeverityLabels as OtherLabel
+func DefaultSeverity(err *Error) string {
+	if s, ok := err.Meta().GetString(MetaSeverity); ok {
+		return s
+	}
+
+	if IsPanic(err) {
+		return SeverityCritical
+	}
+
+	return SeverityError
+}
+
+// SeverityFunc set function to resolve severity label of an error
+func SeverityFunc(fn func(*Error) string) MetricsOptionFn {
+	return func(o *metricsOptions) {
+		o.severity = fn
+	}
+}
+
+// SeverityLabels set allowed severity labels. Default is SeverityDebug, SeverityInfo, SeverityWarning,
+// SeverityError and SeverityCritical
+func SeverityLabels(labels ...string) MetricsOptionFn {
+	return func(o *metricsOptions) {
+		o.severities = make(map[string]struct{}, len(labels))
+		for _, l := range labels {
+			o.severities[l] = struct{}{}
+		}
+	}
+}
+
+type metricsOptions struct {
+	severity   func(*Error) string
+	severities map[string]struct{}
+}
+
+type MetricsOptionFn = func(*metricsOptions)
+
+func evaluateMetricsOptions(args []MetricsOptionFn) *metricsOptions {
+	o := &metricsOptions{
+		severity: DefaultSeverity,
+		severities: map[string]struct{}{
+			SeverityDebug:    {},
+			SeverityInfo:     {},
+			SeverityWarning:  {},
+			SeverityError:    {},
+			SeverityCritical: {},
+		},
+	}
+	for _, fn := range args {
+		fn(o)
+	}
+	return o
+}
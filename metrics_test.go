@@ -0,0 +1,135 @@
+package errx_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"github.com/nbs-go/errx"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsRecord(t *testing.T) {
+	m := errx.NewMetrics()
+
+	notFound := errx.NewError("ERR_1", "Resource not found", errx.WithNamespace("myapp"))
+	m.Record(notFound.Trace())
+	m.Record(notFound.Trace(errx.AddMetadata(errx.MetaSeverity, "warning")))
+	m.Record(fmt.Errorf("failed to save: %w", notFound))
+	m.Record(fmt.Errorf("connection reset"))
+	m.Record(nil)
+
+	samples := m.Snapshot()
+	if len(samples) != 3 {
+		t.Errorf("unexpected samples length. Samples = %+v", samples)
+		return
+	}
+
+	if s := samples[0]; s.Namespace != "" || s.Code != "ERROR" || s.Count != 1 {
+		t.Errorf("unexpected generic error sample. Sample = %+v", s)
+	}
+
+	if s := samples[1]; s.Code != "ERR_1" || s.Severity != "error" || s.Count != 2 || s.Rate <= 0 {
+		t.Errorf("unexpected error sample. Sample = %+v", s)
+	}
+
+	if s := samples[2]; s.Severity != "warning" || s.Count != 1 {
+		t.Errorf("unexpected warning sample. Sample = %+v", s)
+	}
+}
+
+func TestMetricsBoundedByBuilder(t *testing.T) {
+	b := errx.NewBuilder("myapp")
+	registered := b.NewError("ERR_1", "Resource not found")
+
+	m := errx.NewMetrics(errx.SeverityFunc(func(*errx.Error) string {
+		return "high"
+	}), errx.SeverityLabels("high"))
+	m.Register(b)
+
+	m.Record(registered)
+	m.Record(errx.NewError("ERR_99", "Unknown", errx.WithNamespace("myapp")))
+	m.Record(errx.NewError("ERR_1", "Other app", errx.WithNamespace("otherapp")))
+
+	samples := m.Snapshot()
+	if len(samples) != 3 {
+		t.Errorf("unexpected samples length. Samples = %+v", samples)
+		return
+	}
+
+	expected := [][2]string{
+		{"myapp", "ERR_1"},
+		{"myapp", errx.OtherLabel},
+		{errx.OtherLabel, errx.OtherLabel},
+	}
+	for i, s := range samples {
+		if s.Namespace != expected[i][0] || s.Code != expected[i][1] || s.Severity != "high" {
+			t.Errorf("unexpected sample. Sample = %+v", s)
+		}
+	}
+}
+
+func TestMetricsBoundedSeverity(t *testing.T) {
+	m := errx.NewMetrics()
+	for i := 0; i < 3; i++ {
+		m.Record(errx.NewError("ERR_1", "Bad Request", errx.AddMetadata(errx.MetaSeverity, fmt.Sprintf("user-%d", i))))
+	}
+	m.Record(errx.NewError("ERR_1", "Bad Request", errx.AddMetadata(errx.MetaSeverity, errx.SeverityInfo)))
+
+	samples := m.Snapshot()
+	if len(samples) != 2 || samples[0].Severity != errx.SeverityInfo || samples[1].Severity != errx.OtherLabel ||
+		samples[1].Count != 3 {
+		t.Errorf("unexpected severity samples. Samples = %+v", samples)
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	m := errx.NewMetrics()
+	m.Record(errx.NewError("ERR_1", "Bad \"input\"", errx.WithNamespace(`my"app`)))
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type. ContentType = %s", ct)
+	}
+
+	body := w.Body.String()
+	expected := `errx_errors_total{namespace="my\"app",code="ERR_1",severity="error"} 1`
+	if !strings.Contains(body, expected+"\n") {
+		t.Errorf("unexpected exposition body. Body = %s", body)
+	}
+
+	if !strings.Contains(body, "# TYPE errx_errors_rate1m gauge") {
+		t.Errorf("unexpected rate is not exposed. Body = %s", body)
+	}
+}
+
+func TestMetricsExpvar(t *testing.T) {
+	m := errx.NewMetrics()
+	m.Record(errx.InternalError())
+
+	// Publish panics on registered name, so find unused name when test is run repeatedly with -count
+	name := "errx_test_metrics"
+	for i := 1; expvar.Get(name) != nil; i++ {
+		name = fmt.Sprintf("errx_test_metrics_%d", i)
+	}
+	m.Publish(name)
+
+	v := expvar.Get(name)
+	if v == nil {
+		t.Errorf("unexpected metrics is not published")
+		return
+	}
+
+	var samples []errx.MetricSample
+	if err := json.Unmarshal([]byte(v.String()), &samples); err != nil {
+		t.Errorf("unexpected invalid expvar value. Error = %s", err)
+		return
+	}
+
+	if len(samples) != 1 || samples[0].Count != 1 {
+		t.Errorf("unexpected expvar samples. Samples = %+v", samples)
+	}
+}
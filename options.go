@@ -8,15 +8,23 @@ func WithNamespace(namespace string) SetOptionFn {
 	}
 }
 
+// WithMetadata set metadata. Metadata is deep copied, so later changes on input map will not affect the error
 func WithMetadata(metadata map[string]interface{}) SetOptionFn {
 	return func(o *options) {
-		o.metadata = metadata
+		o.metadata = copyMetadata(metadata)
 	}
 }
 
 func AddMetadata(key string, value interface{}) SetOptionFn {
 	return func(o *options) {
-		o.metadata[key] = value
+		o.metadata[key] = deepCopyValue(value)
+	}
+}
+
+// WithMergePolicy set how metadata collision is resolved when merging metadata on Trace. Default is MergeOverride
+func WithMergePolicy(policy MergePolicy) SetOptionFn {
+	return func(o *options) {
+		o.mergePolicy = policy
 	}
 }
 
@@ -38,18 +46,59 @@ func Source(err error) SetOptionFn {
 	}
 }
 
+// WithNote attach note to the trace entry, e.g. "while loading invoice 123"
+func WithNote(note string) SetOptionFn {
+	return func(o *options) {
+		o.note = note
+	}
+}
+
+// Annotate attach formatted note to the trace entry
+func Annotate(format string, args ...interface{}) SetOptionFn {
+	return func(o *options) {
+		o.note = fmt.Sprintf(format, args...)
+	}
+}
+
 func Errorf(msg string, args ...interface{}) SetOptionFn {
 	return func(o *options) {
 		o.sourceErr = fmt.Errorf(msg, args...)
 	}
 }
 
+// WithExitCode set process exit code of error that is used by Main. On NewBuilder, it set default exit code
+// of errors registered in builder
+func WithExitCode(code int) SetOptionFn {
+	return func(o *options) {
+		o.exitCode = code
+	}
+}
+
+// WithRegistry set translator registry of builder. If not set, builder uses DefaultRegistry
+func WithRegistry(r *Registry) SetOptionFn {
+	return func(o *options) {
+		o.registry = r
+	}
+}
+
 type options struct {
 	namespace   string
 	metadata    map[string]interface{}
 	skipTrace   int
 	fallbackErr *Error
 	sourceErr   error
+	mergePolicy MergePolicy
+	exitCode    int
+	registry    *Registry
+	note        string
+	// Global configuration
+	recordTime      bool
+	recordGoroutine bool
+	showElapsed     bool
+	pathFormatter   PathFormatter
+	frames          *frameRules
+	sourceLines     *int
+	formatter       Formatter
 }
 
 type SetOptionFn = func(*options)
@@ -0,0 +1,61 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Attribute keys follow OpenTelemetry semantic conventions for exceptions, plus errx specific code and namespace
+const (
+	AttrExceptionType       = "exception.type"
+	AttrExceptionMessage    = "exception.message"
+	AttrExceptionStacktrace = "exception.stacktrace"
+	AttrErrorCode           = "errx.code"
+	AttrErrorNamespace      = "errx.namespace"
+
+	// ExceptionEventName is the span event name used by OpenTelemetry to record exceptions
+	ExceptionEventName = "exception"
+)
+
+// SpanRecorder is a minimal span abstraction, so errors can be recorded to a tracer without importing its SDK.
+// An OpenTelemetry span can be adapted by converting attributes to attribute.String values
+type SpanRecorder interface {
+	AddEvent(name string, attributes map[string]string)
+}
+
+// RecordError convert error to exception attributes and add them to span as an exception event
+func RecordError(span SpanRecorder, err error) {
+	if span == nil || err == nil {
+		return
+	}
+	span.AddEvent(ExceptionEventName, ExceptionAttributes(err))
+}
+
+// ExceptionAttributes convert error chain into OpenTelemetry exception attributes.
+// If error chain contains *errx.Error, then code and namespace will be taken from the first one found
+func ExceptionAttributes(err error) map[string]string {
+	if err == nil {
+		return nil
+	}
+
+	attrs := map[string]string{
+		AttrExceptionType:       fmt.Sprintf("%T", err),
+		AttrExceptionMessage:    err.Error(),
+		AttrExceptionStacktrace: err.Error(),
+	}
+
+	var xErr *Error
+	if errors.As(err, &xErr) {
+		attrs[AttrErrorCode] = xErr.Code()
+		if ns := xErr.Namespace(); ns != "" {
+			attrs[AttrErrorNamespace] = ns
+		}
+	}
+
+	// If error is *errx.Error, then message is the base error only. Traces and causes are left in stacktrace
+	if tErr, ok := err.(*Error); ok {
+		attrs[AttrExceptionMessage] = tErr.formatBase(false)
+	}
+
+	return attrs
+}
@@ -0,0 +1,75 @@
+package errx_test
+
+import (
+	"fmt"
+	"github.com/nbs-go/errx"
+	"strings"
+	"testing"
+)
+
+type fakeSpan struct {
+	names []string
+	attrs []map[string]string
+}
+
+func (s *fakeSpan) AddEvent(name string, attributes map[string]string) {
+	s.names = append(s.names, name)
+	s.attrs = append(s.attrs, attributes)
+}
+
+func TestRecordError(t *testing.T) {
+	span := new(fakeSpan)
+	err := errx.NewError("ERR_1", "Invalid input", errx.WithNamespace("myapp")).
+		Trace(errx.Errorf("invalid email format"))
+
+	errx.RecordError(span, err)
+
+	if len(span.names) != 1 || span.names[0] != errx.ExceptionEventName {
+		t.Errorf("unexpected recorded events. Events = %+v", span.names)
+		return
+	}
+
+	attrs := span.attrs[0]
+	expected := map[string]string{
+		errx.AttrExceptionType:    "*errx.Error",
+		errx.AttrExceptionMessage: "myapp: [ERR_1] Invalid input",
+		errx.AttrErrorCode:        "ERR_1",
+		errx.AttrErrorNamespace:   "myapp",
+	}
+	for k, v := range expected {
+		if attrs[k] != v {
+			t.Errorf("unexpected attribute %s. Value = %s", k, attrs[k])
+		}
+	}
+
+	if st := attrs[errx.AttrExceptionStacktrace]; !strings.Contains(st, "CausedBy => invalid email format") {
+		t.Errorf("unexpected stacktrace. Stacktrace = %s", st)
+	}
+}
+
+func TestRecordNilError(t *testing.T) {
+	span := new(fakeSpan)
+	errx.RecordError(span, nil)
+
+	if len(span.names) != 0 {
+		t.Errorf("unexpected event recorded for nil error. Events = %+v", span.names)
+	}
+}
+
+func TestExceptionAttributesWrappedError(t *testing.T) {
+	err := fmt.Errorf("failed to save: %w", errx.NewError("ERR_2", "Conflict", errx.WithNamespace("myapp")))
+
+	attrs := errx.ExceptionAttributes(err)
+
+	if v := attrs[errx.AttrExceptionType]; v != "*fmt.wrapError" {
+		t.Errorf("unexpected exception type. Type = %s", v)
+	}
+
+	if v := attrs[errx.AttrExceptionMessage]; v != err.Error() {
+		t.Errorf("unexpected exception message. Message = %s", v)
+	}
+
+	if v := attrs[errx.AttrErrorCode]; v != "ERR_2" {
+		t.Errorf("unexpected code taken from chain. Code = %s", v)
+	}
+}
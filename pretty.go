@@ -0,0 +1,220 @@
+package errx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ANSI escape codes used by Pretty
+const (
+	ansiReset   = "\033[0m"
+	ansiBold    = "\033[1m"
+	ansiDim     = "\033[2m"
+	ansiRed     = "\033[31m"
+	ansiYellow  = "\033[33m"
+	ansiMagenta = "\033[35m"
+	ansiCyan    = "\033[36m"
+)
+
+// Pretty writes error and all its causes to w as an indented tree, with namespace and code badges, metadata and
+// traces. Colors are enabled if w is a terminal and NO_COLOR environment variable is not set, unless set with
+// Color option
+func Pretty(w io.Writer, err error, args ...PrettyOptionFn) {
+	o := evaluatePrettyOptions(args)
+	if o.color == nil {
+		color := isTerminal(w)
+		o.color = &color
+	}
+
+	p := &prettyPrinter{
+		color:     *o.color,
+		traceArgs: o.traceArgs,
+	}
+	p.print(err, "", "", false, nil)
+
+	_, _ = io.WriteString(w, p.buf.String())
+}
+
+// PrettyString returns error tree printed by Pretty. Colors are disabled unless set with Color option
+func PrettyString(err error, args ...PrettyOptionFn) string {
+	var sb strings.Builder
+	Pretty(&sb, err, args...)
+	return sb.String()
+}
+
+type prettyPrinter struct {
+	buf       strings.Builder
+	color     bool
+	traceArgs []SetOptionFn
+}
+
+// prettyNode is a child of error in tree. It is either a section with lines, or a cause
+type prettyNode struct {
+	title string
+	lines []string
+	cause error
+	// merged is true if cause traces has been merged into its wrapper traces
+	merged bool
+	// enclosing is traces of the nearest wrapper that has printed traces
+	enclosing []TraceEntry
+}
+
+// print writes error tree. First is prefix of error header and indent is prefix of its children
+func (p *prettyPrinter) print(err error, first, indent string, merged bool, enclosing []TraceEntry) {
+	if err == nil {
+		return
+	}
+
+	p.buf.WriteString(first + p.header(err) + "\n")
+
+	nodes := p.nodes(err, merged, enclosing)
+	for i, n := range nodes {
+		branch, next := "├─ ", "│  "
+		if i == len(nodes)-1 {
+			branch, next = "└─ ", "   "
+		}
+
+		if n.cause != nil {
+			p.print(n.cause, indent+branch, indent+next, n.merged, n.enclosing)
+			continue
+		}
+
+		p.buf.WriteString(indent + branch + p.paint(ansiBold, n.title) + "\n")
+		for _, line := range n.lines {
+			p.buf.WriteString(indent + next + line + "\n")
+		}
+	}
+}
+
+func (p *prettyPrinter) header(err error) string {
+	if errs, ok := err.(Errors); ok {
+		return p.paint(ansiBold, fmt.Sprintf("%d errors", len(errs)))
+	}
+
+	xErr, ok := err.(*Error)
+	if !ok {
+		return err.Error() + " " + p.paint(ansiDim, fmt.Sprintf("(%T)", err))
+	}
+
+	badges := make([]string, 0, 3)
+	if xErr.panicked {
+		badges = append(badges, p.paint(ansiMagenta, "[panic]"))
+	}
+	if xErr.namespace != "" {
+		badges = append(badges, p.paint(ansiCyan, "["+xErr.namespace+"]"))
+	}
+	badges = append(badges, p.paint(ansiBold+ansiRed, "["+xErr.code+"]"))
+
+	return strings.Join(badges, " ") + " " + xErr.message
+}
+
+// nodes returns metadata, traces and causes of error. Traces are not printed if merged into wrapper traces,
+// and frames shared with enclosing traces are printed as "... N more"
+func (p *prettyPrinter) nodes(err error, merged bool, enclosing []TraceEntry) []prettyNode {
+	nodes := make([]prettyNode, 0)
+
+	if errs, ok := err.(Errors); ok {
+		for _, cErr := range errs {
+			nodes = append(nodes, prettyNode{cause: cErr, enclosing: enclosing})
+		}
+		return nodes
+	}
+
+	xErr, ok := err.(*Error)
+	if !ok {
+		// Generic error message already contains its wrapped errors, so only errx.Error causes are printed
+		if cErr, ok := errors.Unwrap(err).(*Error); ok {
+			nodes = append(nodes, prettyNode{cause: cErr, enclosing: enclosing})
+		}
+		return nodes
+	}
+
+	if len(xErr.metadata) > 0 {
+		nodes = append(nodes, prettyNode{title: "metadata", lines: p.metadataLines(xErr.metadata)})
+	}
+
+	if !merged && len(xErr.traces) > 0 {
+		lines := xErr.config(p.traceArgs...).enclosedTraceLines(xErr.traces, enclosing)
+		for i, line := range lines {
+			lines[i] = p.paint(ansiDim, line)
+		}
+		nodes = append(nodes, prettyNode{title: "traces", lines: lines})
+		enclosing = xErr.traces
+	}
+
+	if xErr.sourceErr != nil {
+		nodes = append(nodes, prettyNode{cause: xErr.sourceErr, merged: xErr.mergedSource, enclosing: enclosing})
+	}
+
+	return nodes
+}
+
+func (p *prettyPrinter) metadataLines(metadata map[string]interface{}) []string {
+	keys := make([]string, 0, len(metadata))
+	width := 0
+	for k := range metadata {
+		keys = append(keys, k)
+		if len(k) > width {
+			width = len(k)
+		}
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, len(keys))
+	for i, k := range keys {
+		lines[i] = p.paint(ansiYellow, fmt.Sprintf("%-*s", width, k)) + "  " + fmt.Sprintf("%v", metadata[k])
+	}
+	return lines
+}
+
+func (p *prettyPrinter) paint(code, s string) string {
+	if !p.color {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// isTerminal check if w is a character device, such as terminal, and colors are not disabled by NO_COLOR
+// environment variable or dumb terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Color enables or disables ANSI colors in Pretty output
+func Color(enabled bool) PrettyOptionFn {
+	return func(o *prettyOptions) {
+		o.color = &enabled
+	}
+}
+
+// PrettyTraces set options to render traces in Pretty output, e.g. PrettyTraces(TracePath(BaseName))
+func PrettyTraces(args ...SetOptionFn) PrettyOptionFn {
+	return func(o *prettyOptions) {
+		o.traceArgs = append(o.traceArgs, args...)
+	}
+}
+
+type prettyOptions struct {
+	color     *bool
+	traceArgs []SetOptionFn
+}
+
+type PrettyOptionFn = func(*prettyOptions)
+
+func evaluatePrettyOptions(args []PrettyOptionFn) *prettyOptions {
+	o := new(prettyOptions)
+	for _, fn := range args {
+		fn(o)
+	}
+	return o
+}
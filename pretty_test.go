@@ -0,0 +1,64 @@
+package errx_test
+
+import (
+	"errors"
+	"github.com/nbs-go/errx"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPrettyString(t *testing.T) {
+	b := errx.NewBuilder("myapp")
+	notFound := b.NewError("E_NOT_FOUND", "Invoice not found")
+
+	srcErr := errx.Trace(errors.New("no rows"))
+	err := notFound.Trace(errx.Source(srcErr), errx.AddMetadata("invoiceId", 42), errx.AddMetadata("status", "open"),
+		errx.WithNote("while loading invoice"))
+
+	expected := "[myapp] [E_NOT_FOUND] Invoice not found\n" +
+		"├─ metadata\n" +
+		"│  invoiceId  42\n" +
+		"│  status     open\n" +
+		"├─ traces\n" +
+		"│  pretty_test.go:16 (while loading invoice)\n" +
+		"│  pretty_test.go:15\n" +
+		"└─ [ERROR] Internal Error\n" +
+		"   └─ no rows (*errors.errorString)\n"
+	if actual := errx.PrettyString(err, errx.PrettyTraces(errx.TracePath(errx.BaseName))); actual != expected {
+		t.Errorf("unexpected pretty string. Actual =\n%s", actual)
+	}
+}
+
+func TestPrettyColor(t *testing.T) {
+	err := errx.NewError("ERR_1", "Bad Request", errx.WithNamespace("myapp"))
+
+	if actual := errx.PrettyString(err, errx.Color(true)); !strings.Contains(actual, "\033[1m\033[31m[ERR_1]\033[0m") {
+		t.Errorf("unexpected colorless output. Actual = %q", actual)
+	}
+
+	// Output is not a terminal
+	f, fErr := os.CreateTemp(t.TempDir(), "pretty")
+	if fErr != nil {
+		t.Errorf("unexpected error on create file. Error = %s", fErr)
+		return
+	}
+	defer f.Close()
+
+	errx.Pretty(f, err)
+	b, _ := os.ReadFile(f.Name())
+	if string(b) != "[myapp] [ERR_1] Bad Request\n" {
+		t.Errorf("unexpected output on file. Output = %q", b)
+	}
+}
+
+func TestPrettyErrors(t *testing.T) {
+	errs := errx.Errors{errx.NewError("ERR_1", "First"), errx.NewError("ERR_2", "Second")}
+
+	expected := "2 errors\n" +
+		"├─ [ERR_1] First\n" +
+		"└─ [ERR_2] Second\n"
+	if actual := errx.PrettyString(errs); actual != expected {
+		t.Errorf("unexpected pretty string. Actual =\n%s", actual)
+	}
+}
@@ -0,0 +1,90 @@
+package errx
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Recover recovers from panic and set recovered value as *errx.Error to errp. It must be called directly by defer,
+// e.g. defer errx.Recover(&err). If errp is nil, panic is recovered and discarded
+func Recover(errp *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	err := fromPanic(r)
+	if errp != nil {
+		*errp = err
+	}
+}
+
+// Go runs fn in a new goroutine and recovers panic as *errx.Error. Returned channel receives fn result once,
+// then it will be closed
+func Go(fn func() error) <-chan error {
+	ch := make(chan error, 1)
+	go func() {
+		var err error
+		defer func() {
+			ch <- err
+			close(ch)
+		}()
+		defer Recover(&err)
+		err = fn()
+	}()
+	return ch
+}
+
+// IsPanic check if error or one of its causes is recovered from panic
+func IsPanic(err error) bool {
+	return walk(err, func(err error) bool {
+		xErr, ok := err.(*Error)
+		return ok && xErr.panicked
+	})
+}
+
+// fromPanic convert recovered value to *errx.Error. If value is an error, then it will be set as source.
+// Traces are filled with stack trace of panic site
+func fromPanic(r interface{}) *Error {
+	srcErr, ok := r.(error)
+	if !ok {
+		srcErr = fmt.Errorf("%v", r)
+	}
+
+	nErr, traces := PanicError().wrapAndTrace(srcErr)
+	nErr.panicked = true
+	nErr.traces = append(panicStack(), traces...)
+
+	return nErr
+}
+
+// panicStack returns stack trace from panic site, excluding runtime frames
+func panicStack() []TraceEntry {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	stack := make([]TraceEntry, 0)
+	inPanic := false
+	for {
+		f, more := frames.Next()
+		switch {
+		case f.Function == "runtime.gopanic":
+			inPanic = true
+		case inPanic && !strings.HasPrefix(f.Function, "runtime."):
+			stack = append(stack, TraceEntry{File: f.File, Line: f.Line, Function: f.Function})
+		}
+		if !more {
+			break
+		}
+	}
+
+	entries := make([]*TraceEntry, len(stack))
+	for i := range stack {
+		entries[i] = &stack[i]
+	}
+	stamp(loadConfig(), entries...)
+
+	return stack
+}
@@ -0,0 +1,106 @@
+package errx_test
+
+import (
+	"errors"
+	"github.com/nbs-go/errx"
+	"strings"
+	"testing"
+)
+
+func panicWithValue() (err error) {
+	defer errx.Recover(&err)
+	panic("unexpected state")
+}
+
+func panicWithError(src error) (err error) {
+	defer errx.Recover(&err)
+	panic(src)
+}
+
+func panicWithNilPointer() (err error) {
+	defer errx.Recover(&err)
+	var m map[string]int
+	m["key"] = 1
+	return nil
+}
+
+func TestRecoverValue(t *testing.T) {
+	err := panicWithValue()
+
+	if !errors.Is(err, errx.PanicError()) {
+		t.Errorf("unexpected recovered error. Error = %s", err)
+		return
+	}
+
+	if !errx.IsPanic(err) {
+		t.Errorf("unexpected recovered error is not marked as panic")
+	}
+
+	if cause := errors.Unwrap(err); cause == nil || cause.Error() != "unexpected state" {
+		t.Errorf("unexpected recovered cause. Cause = %v", cause)
+	}
+
+	traces := err.(*errx.Error).Traces()
+	if len(traces) == 0 {
+		t.Errorf("unexpected empty panic traces")
+		return
+	}
+
+	if !strings.HasSuffix(traces[0], "recover_test.go:12") {
+		t.Errorf("unexpected panic site. Trace = %s", traces[0])
+	}
+
+	t.Logf("Error = %s", err)
+}
+
+func TestRecoverError(t *testing.T) {
+	srcErr := errx.NewError("ERR_1", "Invalid state")
+	err := panicWithError(srcErr)
+
+	if !errors.Is(err, srcErr) {
+		t.Errorf("unexpected recovered error does not keep source. Error = %s", err)
+	}
+}
+
+func TestRecoverRuntimeError(t *testing.T) {
+	err := panicWithNilPointer()
+
+	traces := err.(*errx.Error).Traces()
+	if len(traces) == 0 || !strings.HasSuffix(traces[0], "recover_test.go:23") {
+		t.Errorf("unexpected panic site. Traces = %+v", traces)
+	}
+}
+
+func TestRecoverNoPanic(t *testing.T) {
+	err := func() (err error) {
+		defer errx.Recover(&err)
+		return nil
+	}()
+
+	if err != nil {
+		t.Errorf("unexpected error without panic. Error = %s", err)
+	}
+
+	if errx.IsPanic(errx.InternalError()) {
+		t.Errorf("unexpected non panic error is marked as panic")
+	}
+}
+
+func TestGo(t *testing.T) {
+	err := <-errx.Go(func() error {
+		panic("goroutine failure")
+	})
+
+	if !errx.IsPanic(err) {
+		t.Errorf("unexpected error from goroutine. Error = %v", err)
+	}
+
+	srcErr := errx.NewError("ERR_1", "Invalid state")
+	err = <-errx.Go(func() error {
+		return srcErr
+	})
+
+	if err != srcErr {
+		t.Errorf("unexpected returned error from goroutine. Error = %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package sentry
+
+// NewClient creates a client that sends events through transport
+func NewClient(transport Transport, args ...SetOptionFn) *Client {
+	o := evaluateOptions(args)
+	return &Client{
+		transport:   transport,
+		environment: o.environment,
+		release:     o.release,
+	}
+}
+
+// Client captures error as event and sends it through Transport
+type Client struct {
+	transport   Transport
+	environment string
+	release     string
+}
+
+// Capture builds event from error and send it. It returns event id
+func (c *Client) Capture(err error) (string, error) {
+	if err == nil {
+		return "", nil
+	}
+
+	event := NewEvent(err)
+	event.Environment = c.environment
+	event.Release = c.release
+
+	if sErr := c.transport.Send(event); sErr != nil {
+		return "", sErr
+	}
+
+	return event.EventID, nil
+}
+
+func WithEnvironment(environment string) SetOptionFn {
+	return func(o *options) {
+		o.environment = environment
+	}
+}
+
+func WithRelease(release string) SetOptionFn {
+	return func(o *options) {
+		o.release = release
+	}
+}
+
+type options struct {
+	environment string
+	release     string
+}
+
+type SetOptionFn = func(*options)
+
+func evaluateOptions(args []SetOptionFn) *options {
+	o := new(options)
+	for _, fn := range args {
+		fn(o)
+	}
+	return o
+}
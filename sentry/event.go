@@ -0,0 +1,173 @@
+// Package sentry builds Sentry-compatible events from errx error chain and sends them through a pluggable Transport
+package sentry
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/nbs-go/errx"
+	"path/filepath"
+	"time"
+)
+
+const (
+	TagNamespace = "errx.namespace"
+	TagCode      = "errx.code"
+)
+
+// VarNote is frame variable name of trace note
+const VarNote = "note"
+
+// Event is a Sentry-compatible event payload
+type Event struct {
+	EventID     string                 `json:"event_id"`
+	Timestamp   time.Time              `json:"timestamp"`
+	Level       string                 `json:"level"`
+	Platform    string                 `json:"platform"`
+	Message     string                 `json:"message,omitempty"`
+	Environment string                 `json:"environment,omitempty"`
+	Release     string                 `json:"release,omitempty"`
+	Exception   *ExceptionList         `json:"exception,omitempty"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+}
+
+// ExceptionList holds chained exceptions, sorted from the innermost cause to the outermost error
+type ExceptionList struct {
+	Values []Exception `json:"values"`
+}
+
+// Exception is a single error in chain
+type Exception struct {
+	Type       string      `json:"type"`
+	Value      string      `json:"value"`
+	Module     string      `json:"module,omitempty"`
+	Stacktrace *Stacktrace `json:"stacktrace,omitempty"`
+}
+
+// Stacktrace holds frames, sorted from the oldest call to the most recent one
+type Stacktrace struct {
+	Frames []Frame `json:"frames"`
+}
+
+// Frame is a single stack frame parsed from errx trace
+type Frame struct {
+	Filename string            `json:"filename"`
+	Function string            `json:"function,omitempty"`
+	AbsPath  string            `json:"abs_path,omitempty"`
+	Lineno   int               `json:"lineno,omitempty"`
+	Vars     map[string]string `json:"vars,omitempty"`
+}
+
+// NewEvent creates event from error chain. Each error in chain become an exception, including each of aggregated
+// errx.Errors. Tags are taken from the outermost *errx.Error and extra is merged from metadata of all *errx.Error
+// in chain
+func NewEvent(err error) *Event {
+	e := &Event{
+		EventID:   newEventID(),
+		Timestamp: time.Now().UTC(),
+		Level:     "error",
+		Platform:  "go",
+		Tags:      make(map[string]string),
+		Extra:     make(map[string]interface{}),
+	}
+
+	if err == nil {
+		return e
+	}
+
+	// Set tags from the outermost errx.Error
+	var xErr *errx.Error
+	if errors.As(err, &xErr) {
+		e.Tags[TagCode] = xErr.Code()
+		if ns := xErr.Namespace(); ns != "" {
+			e.Tags[TagNamespace] = ns
+		}
+	}
+
+	values := appendExceptions(make([]Exception, 0), err, e.Extra)
+
+	// Sort from the innermost cause
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+	e.Exception = &ExceptionList{Values: values}
+	e.Message = values[len(values)-1].Value
+
+	return e
+}
+
+// appendExceptions appends exception of each error in chain from the outermost, and merges metadata into extra.
+// Each of aggregated errx.Errors, e.g. failures of Group, is appended with its own chain
+func appendExceptions(values []Exception, err error, extra map[string]interface{}) []Exception {
+	for cur := err; cur != nil; cur = errors.Unwrap(cur) {
+		if errs, ok := cur.(errx.Errors); ok {
+			for _, xErr := range errs {
+				values = appendExceptions(values, xErr, extra)
+			}
+			return values
+		}
+
+		values = append(values, newException(cur))
+
+		// Merge metadata, outer error has higher priority
+		if cErr, ok := cur.(*errx.Error); ok {
+			for k, v := range cErr.Metadata() {
+				if _, exists := extra[k]; !exists {
+					extra[k] = v
+				}
+			}
+		}
+	}
+	return values
+}
+
+func newException(err error) Exception {
+	xErr, ok := err.(*errx.Error)
+	if !ok {
+		return Exception{
+			Type:  fmt.Sprintf("%T", err),
+			Value: err.Error(),
+		}
+	}
+
+	ex := Exception{
+		Type:   xErr.Code(),
+		Value:  xErr.Message(),
+		Module: xErr.Namespace(),
+	}
+
+	if traces := xErr.TraceEntries(); len(traces) > 0 {
+		// Traces are sorted from the most recent call, while Sentry expects the oldest call first
+		frames := make([]Frame, len(traces))
+		for i, t := range traces {
+			frames[len(traces)-1-i] = newFrame(t)
+		}
+		ex.Stacktrace = &Stacktrace{Frames: frames}
+	}
+
+	return ex
+}
+
+// newFrame converts errx trace entry to frame. Trace note is set to frame vars
+func newFrame(t errx.TraceEntry) Frame {
+	f := Frame{
+		Filename: filepath.Base(t.File),
+		Function: t.Function,
+		Lineno:   t.Line,
+	}
+	if filepath.IsAbs(t.File) {
+		f.AbsPath = t.File
+	}
+	if t.Note != "" {
+		f.Vars = map[string]string{VarNote: t.Note}
+	}
+	return f
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
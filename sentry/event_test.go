@@ -0,0 +1,154 @@
+package sentry_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/nbs-go/errx"
+	"github.com/nbs-go/errx/sentry"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newChainError() error {
+	srcErr := errx.NewError("ERR_1", "Record not found", errx.WithNamespace("db"),
+		errx.AddMetadata("table", "invoices")).Trace(errx.Errorf("no rows in result set"))
+	return errx.NewError("ERR_2", "Invoice not found", errx.WithNamespace("myapp"),
+		errx.AddMetadata("invoiceId", 42)).Trace(errx.Source(srcErr))
+}
+
+func TestNewEvent(t *testing.T) {
+	event := sentry.NewEvent(newChainError())
+
+	if event.Tags[sentry.TagCode] != "ERR_2" || event.Tags[sentry.TagNamespace] != "myapp" {
+		t.Errorf("unexpected tags. Tags = %+v", event.Tags)
+	}
+
+	if event.Extra["table"] != "invoices" || event.Extra["invoiceId"] != 42 {
+		t.Errorf("unexpected extra. Extra = %+v", event.Extra)
+	}
+
+	values := event.Exception.Values
+	if len(values) != 3 {
+		t.Errorf("unexpected exceptions length. Length = %d", len(values))
+		return
+	}
+
+	if v := values[0]; v.Type != "*errors.errorString" || v.Value != "no rows in result set" {
+		t.Errorf("unexpected innermost exception. Exception = %+v", v)
+	}
+
+	if v := values[1]; v.Type != "ERR_1" || v.Module != "db" || v.Stacktrace == nil {
+		t.Errorf("unexpected source exception. Exception = %+v", v)
+	}
+
+	last := values[2]
+	if last.Type != "ERR_2" || last.Value != "Invoice not found" || event.Message != last.Value {
+		t.Errorf("unexpected outermost exception. Exception = %+v", last)
+	}
+
+	frames := last.Stacktrace.Frames
+	if len(frames) != 2 {
+		t.Errorf("unexpected frames length. Length = %d", len(frames))
+		return
+	}
+
+	// The most recent call must be the last frame
+	if f := frames[1]; f.Filename != "event_test.go" || f.Lineno != 20 {
+		t.Errorf("unexpected most recent frame. Frame = %+v", f)
+	}
+
+	if len(event.EventID) != 32 {
+		t.Errorf("unexpected event id. EventID = %s", event.EventID)
+	}
+}
+
+func TestNewEventGroupFailure(t *testing.T) {
+	g, _ := errx.NewGroup(context.Background())
+	g.Go(func() error {
+		return newChainError()
+	})
+	g.Go(func() error {
+		return errors.New("connection reset")
+	})
+
+	values := sentry.NewEvent(g.Wait()).Exception.Values
+	if len(values) != 6 {
+		t.Errorf("unexpected exceptions length. Exceptions = %+v", values)
+		return
+	}
+
+	// Chain of each failure, sorted from the innermost cause
+	expected := []string{"connection reset", "Internal Error", "no rows in result set", "Record not found",
+		"Invoice not found", "One or more tasks failed"}
+	for i, v := range values {
+		if v.Value != expected[i] {
+			t.Errorf("unexpected exception. Index = %d, Exception = %+v", i, v)
+		}
+	}
+}
+
+func TestClientCapture(t *testing.T) {
+	transport := sentry.NewMemoryTransport()
+	client := sentry.NewClient(transport, sentry.WithEnvironment("test"), sentry.WithRelease("1.0.0"))
+
+	id, err := client.Capture(fmt.Errorf("connection reset"))
+	if err != nil {
+		t.Errorf("unexpected capture error. Error = %s", err)
+		return
+	}
+
+	events := transport.Events()
+	if len(events) != 1 || events[0].EventID != id {
+		t.Errorf("unexpected sent events. Events = %+v", events)
+		return
+	}
+
+	if e := events[0]; e.Environment != "test" || e.Release != "1.0.0" || len(e.Tags) != 0 {
+		t.Errorf("unexpected event. Event = %+v", e)
+	}
+
+	if id, _ = client.Capture(nil); id != "" || len(transport.Events()) != 1 {
+		t.Errorf("unexpected nil error is captured")
+	}
+}
+
+func TestFileTransport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	transport, err := sentry.NewFileTransport(path)
+	if err != nil {
+		t.Errorf("unexpected error on creating transport. Error = %s", err)
+		return
+	}
+
+	client := sentry.NewClient(transport)
+	_, _ = client.Capture(newChainError())
+	_, _ = client.Capture(errx.InternalError())
+	_ = transport.Close()
+
+	f, _ := os.Open(path)
+	defer f.Close()
+
+	var events []sentry.Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e sentry.Event
+		if err = json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Errorf("unexpected invalid event line. Error = %s", err)
+			return
+		}
+		events = append(events, e)
+	}
+
+	if len(events) != 2 {
+		t.Errorf("unexpected written events length. Length = %d", len(events))
+		return
+	}
+
+	if v := events[1].Exception.Values[0]; v.Type != "ERROR" {
+		t.Errorf("unexpected exception in file. Exception = %+v", v)
+	}
+}
@@ -0,0 +1,68 @@
+package sentry
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// Transport sends event to a Sentry-compatible collector
+type Transport interface {
+	Send(event *Event) error
+}
+
+// NewMemoryTransport creates a Transport that keeps events in memory
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{}
+}
+
+// MemoryTransport keeps sent events in memory. It is intended for tests
+type MemoryTransport struct {
+	mu     sync.Mutex
+	events []*Event
+}
+
+// Send stores event in memory
+func (t *MemoryTransport) Send(event *Event) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+	return nil
+}
+
+// Events returns sent events
+func (t *MemoryTransport) Events() []*Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	events := make([]*Event, len(t.events))
+	copy(events, t.events)
+	return events
+}
+
+// NewFileTransport creates a Transport that appends events as JSON lines to file
+func NewFileTransport(path string) (*FileTransport, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileTransport{file: f, enc: json.NewEncoder(f)}, nil
+}
+
+// FileTransport writes each event as a JSON line to file
+type FileTransport struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// Send writes event to file
+func (t *FileTransport) Send(event *Event) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.enc.Encode(event)
+}
+
+// Close closes underlying file
+func (t *FileTransport) Close() error {
+	return t.file.Close()
+}
@@ -0,0 +1,97 @@
+package errx
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SourceEnv is environment variable to show source snippet around traces in Error output. Value is number of lines
+// printed before and after trace line, or true to print 2 lines
+const SourceEnv = "ERRX_SOURCE"
+
+const defaultSourceLines = 2
+
+// ShowSource prints source snippet of n lines before and after each trace line in Error output, with trace line
+// marked by ">". Source is read from disk and cached, so it should only be enabled in development. Traces whose
+// source file is missing are printed without snippet. Use ShowSource(0) to disable it
+func ShowSource(n int) SetOptionFn {
+	return func(o *options) {
+		o.sourceLines = &n
+	}
+}
+
+var (
+	sourceEnvOnce  sync.Once
+	sourceEnvLines int
+)
+
+// sourceLinesFromEnv parse number of source lines from SourceEnv
+func sourceLinesFromEnv() int {
+	sourceEnvOnce.Do(func() {
+		v := strings.ToLower(os.Getenv(SourceEnv))
+		switch v {
+		case "1", "true", "yes":
+			sourceEnvLines = defaultSourceLines
+		default:
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				sourceEnvLines = n
+			}
+		}
+	})
+	return sourceEnvLines
+}
+
+// sourceCache caches lines of source files. Missing files are cached as nil, so disk is only accessed once per file
+var sourceCache = struct {
+	mu    sync.Mutex
+	files map[string][]string
+}{files: make(map[string][]string)}
+
+func readSource(file string) []string {
+	sourceCache.mu.Lock()
+	defer sourceCache.mu.Unlock()
+
+	lines, ok := sourceCache.files[file]
+	if ok {
+		return lines
+	}
+
+	if b, err := os.ReadFile(file); err == nil {
+		b = bytes.ReplaceAll(b, []byte("\t"), []byte("    "))
+		lines = strings.Split(string(bytes.TrimRight(b, "\n")), "\n")
+	}
+	sourceCache.files[file] = lines
+	return lines
+}
+
+// sourceSnippet returns n source lines around trace line, e.g. "  > 42 | return errx.Trace(err)".
+// It returns nil if source file is not available
+func sourceSnippet(t TraceEntry, n int) []string {
+	lines := readSource(t.File)
+	if t.Line < 1 || t.Line > len(lines) {
+		return nil
+	}
+
+	start, end := t.Line-n, t.Line+n
+	if start < 1 {
+		start = 1
+	}
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	width := len(strconv.Itoa(end))
+	snippet := make([]string, 0, end-start+1)
+	for i := start; i <= end; i++ {
+		mark := " "
+		if i == t.Line {
+			mark = ">"
+		}
+		snippet = append(snippet, fmt.Sprintf("  %s %*d | %s", mark, width, i, lines[i-1]))
+	}
+	return snippet
+}
@@ -0,0 +1,46 @@
+package errx_test
+
+import (
+	"encoding/json"
+	"github.com/nbs-go/errx"
+	"strings"
+	"testing"
+)
+
+func TestShowSource(t *testing.T) {
+	err := errx.InternalError().Trace()
+
+	expected := "Internal Error" +
+		"\n  Traces => github.com/nbs-go/errx/source_test.go:11" +
+		"\n                10 | func TestShowSource(t *testing.T) {" +
+		"\n              > 11 |     err := errx.InternalError().Trace()" +
+		"\n                12 | "
+	if msg := err.Render(errx.ShowSource(1)); msg != expected {
+		t.Errorf("unexpected source snippet. Message = %s", msg)
+	}
+
+	// Builder option and disable on call
+	b := errx.NewBuilder("myapp", errx.ShowSource(2))
+	bErr := b.NewError("E_NOT_FOUND", "Not found").Trace()
+	if msg := bErr.Error(); !strings.Contains(msg, "> 24 |     bErr := b.NewError") ||
+		!strings.Contains(msg, "  22 |     // Builder option") {
+		t.Errorf("unexpected builder source snippet. Message = %s", msg)
+	}
+
+	if msg := bErr.Render(errx.ShowSource(0)); strings.Contains(msg, "|") {
+		t.Errorf("unexpected source snippet is printed. Message = %s", msg)
+	}
+}
+
+func TestShowSourceMissing(t *testing.T) {
+	var err errx.Error
+	data := `{"message": "Internal Error", "traces": [{"file": "/errx/not/exist.go", "line": 10}]}`
+	if jErr := json.Unmarshal([]byte(data), &err); jErr != nil {
+		t.Errorf("unexpected error on unmarshal. Error = %s", jErr)
+		return
+	}
+
+	if msg := err.Render(errx.ShowSource(2)); msg != "Internal Error\n  Traces => /errx/not/exist.go:10" {
+		t.Errorf("unexpected message of missing source. Message = %s", msg)
+	}
+}
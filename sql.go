@@ -0,0 +1,160 @@
+package errx
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SQLStateError is implemented by database driver errors that expose SQLSTATE code
+type SQLStateError interface {
+	SQLState() string
+}
+
+// Common SQLSTATE codes
+const (
+	SQLStateNotNullViolation     = "23502"
+	SQLStateForeignKeyViolation  = "23503"
+	SQLStateUniqueViolation      = "23505"
+	SQLStateSerializationFailure = "40001"
+)
+
+// MetaSQLState is metadata key of SQLSTATE code set by SQL translator
+const MetaSQLState = "sqlState"
+
+// Default errors of database/sql error classes
+var (
+	NoRowsError               = NewError("NO_ROWS", "Record not found")
+	UniqueViolationError      = NewError("UNIQUE_VIOLATION", "Record already exists")
+	ForeignKeyViolationError  = NewError("FOREIGN_KEY_VIOLATION", "Referenced record does not exist")
+	NotNullViolationError     = NewError("NOT_NULL_VIOLATION", "Required value is empty")
+	SerializationFailureError = NewError("SERIALIZATION_FAILURE", "Transaction serialization failure, retry")
+)
+
+// SQLMapping configures errors that SQL errors are translated into. Nil error is replaced with its default error
+type SQLMapping struct {
+	NoRows               *Error
+	UniqueViolation      *Error
+	ForeignKeyViolation  *Error
+	NotNullViolation     *Error
+	SerializationFailure *Error
+	// States maps additional SQLSTATE codes
+	States map[string]*Error
+}
+
+// RegisterSQL registers translator of sql.ErrNoRows and driver errors classified by SQLSTATE to registry
+func RegisterSQL(r *Registry, m SQLMapping) {
+	states := map[string]*Error{
+		SQLStateUniqueViolation:      orDefault(m.UniqueViolation, UniqueViolationError),
+		SQLStateForeignKeyViolation:  orDefault(m.ForeignKeyViolation, ForeignKeyViolationError),
+		SQLStateNotNullViolation:     orDefault(m.NotNullViolation, NotNullViolationError),
+		SQLStateSerializationFailure: orDefault(m.SerializationFailure, SerializationFailureError),
+	}
+	for state, tErr := range m.States {
+		states[state] = tErr
+	}
+
+	r.RegisterIs(sql.ErrNoRows, orDefault(m.NoRows, NoRowsError))
+	r.Register(func(err error) *Error {
+		state, ok := SQLState(err)
+		if !ok {
+			return nil
+		}
+
+		tErr, ok := states[state]
+		if !ok {
+			return nil
+		}
+
+		return tErr.Copy(AddMetadata(MetaSQLState, state))
+	})
+}
+
+// SQLState returns SQLSTATE code of the first error in chain that implements SQLStateError
+func SQLState(err error) (string, bool) {
+	var sErr SQLStateError
+	if errors.As(err, &sErr) {
+		return sErr.SQLState(), true
+	}
+	return "", false
+}
+
+func orDefault(err, defaultErr *Error) *Error {
+	if err != nil {
+		return err
+	}
+	return defaultErr
+}
+
+// CodeRef is a reference to error by its namespace and code. It implements driver.Valuer and sql.Scanner,
+// so error code can be stored in database, e.g. in audit tables. It is stored as "namespace:code", or only code
+// if namespace is empty
+type CodeRef struct {
+	Namespace string
+	Code      string
+}
+
+// Ref returns reference to error code
+func (e *Error) Ref() CodeRef {
+	return CodeRef{Namespace: e.namespace, Code: e.code}
+}
+
+// ParseCodeRef parses reference in "namespace:code" or "code" format
+func ParseCodeRef(s string) CodeRef {
+	if i := strings.LastIndexByte(s, ':'); i >= 0 {
+		return CodeRef{Namespace: s[:i], Code: s[i+1:]}
+	}
+	return CodeRef{Code: s}
+}
+
+// String returns reference in "namespace:code" or "code" format
+func (c CodeRef) String() string {
+	if c.Namespace == "" {
+		return c.Code
+	}
+	return c.Namespace + ":" + c.Code
+}
+
+// IsZero check if reference is empty
+func (c CodeRef) IsZero() bool {
+	return c.Namespace == "" && c.Code == ""
+}
+
+// Is check if reference points to error
+func (c CodeRef) Is(err *Error) bool {
+	return err != nil && err.namespace == c.Namespace && err.code == c.Code
+}
+
+// Resolve retrieve referenced error from builder. If namespace does not match or code is not registered,
+// then builder fallback error is returned
+func (c CodeRef) Resolve(b *Builder) *Error {
+	if c.Namespace != b.Namespace() {
+		return b.FallbackError()
+	}
+	return b.Get(c.Code)
+}
+
+// Value implements driver.Valuer interface. Empty reference is stored as NULL
+func (c CodeRef) Value() (driver.Value, error) {
+	if c.IsZero() {
+		return nil, nil
+	}
+	return c.String(), nil
+}
+
+// Scan implements sql.Scanner interface
+func (c *CodeRef) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*c = CodeRef{}
+	case string:
+		*c = ParseCodeRef(v)
+	case []byte:
+		*c = ParseCodeRef(string(v))
+	default:
+		return fmt.Errorf("errx: cannot scan %T into CodeRef", src)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package errx_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/nbs-go/errx"
+	"testing"
+)
+
+type testDriverError struct {
+	state string
+}
+
+func (e *testDriverError) Error() string {
+	return "driver error " + e.state
+}
+
+func (e *testDriverError) SQLState() string {
+	return e.state
+}
+
+func TestRegisterSQL(t *testing.T) {
+	b := errx.NewBuilder("myapp")
+	notFound := b.NewError("E_NOT_FOUND", "Invoice not found")
+	duplicate := b.NewError("E_DUPLICATE", "Invoice number already exists")
+	checkViolation := b.NewError("E_CHECK", "Invalid invoice value")
+
+	r := errx.NewRegistry()
+	errx.RegisterSQL(r, errx.SQLMapping{
+		NoRows:          notFound,
+		UniqueViolation: duplicate,
+		States: map[string]*errx.Error{
+			"23514": checkViolation,
+		},
+	})
+
+	testCases := []struct {
+		err      error
+		expected *errx.Error
+	}{
+		{fmt.Errorf("find invoice: %w", sql.ErrNoRows), notFound},
+		{&testDriverError{state: errx.SQLStateUniqueViolation}, duplicate},
+		{fmt.Errorf("insert: %w", &testDriverError{state: errx.SQLStateForeignKeyViolation}), errx.ForeignKeyViolationError},
+		{&testDriverError{state: errx.SQLStateNotNullViolation}, errx.NotNullViolationError},
+		{&testDriverError{state: errx.SQLStateSerializationFailure}, errx.SerializationFailureError},
+		{&testDriverError{state: "23514"}, checkViolation},
+	}
+
+	for i, tc := range testCases {
+		tErr, ok := r.Translate(tc.err)
+		if !ok || !errors.Is(tErr, tc.expected) {
+			t.Errorf("unexpected translated error. Index = %d, Translated = %v", i, tErr)
+		}
+	}
+
+	if _, ok := r.Translate(&testDriverError{state: "08006"}); ok {
+		t.Errorf("unexpected unmapped SQLSTATE is translated")
+	}
+
+	tErr, _ := r.Translate(&testDriverError{state: errx.SQLStateUniqueViolation})
+	if v, _ := tErr.Meta().GetString(errx.MetaSQLState); v != errx.SQLStateUniqueViolation {
+		t.Errorf("unexpected sqlState metadata. Value = %s", v)
+	}
+
+	if duplicate.Meta().Len() != 0 {
+		t.Errorf("unexpected mapped error metadata is modified")
+	}
+}
+
+func TestSQLState(t *testing.T) {
+	if state, ok := errx.SQLState(fmt.Errorf("exec: %w", &testDriverError{state: "40001"})); !ok || state != "40001" {
+		t.Errorf("unexpected SQLSTATE. State = %s", state)
+	}
+
+	if _, ok := errx.SQLState(sql.ErrNoRows); ok {
+		t.Errorf("unexpected SQLSTATE found in generic error")
+	}
+}
+
+func TestCodeRef(t *testing.T) {
+	b := errx.NewBuilder("myapp")
+	notFound := b.NewError("E_NOT_FOUND", "Invoice not found")
+
+	ref := notFound.Ref()
+	v, err := ref.Value()
+	if err != nil || v != "myapp:E_NOT_FOUND" {
+		t.Errorf("unexpected value. Value = %v, Error = %v", v, err)
+	}
+
+	var scanned errx.CodeRef
+	if err = scanned.Scan([]byte("myapp:E_NOT_FOUND")); err != nil || scanned != ref {
+		t.Errorf("unexpected scanned bytes. Ref = %+v, Error = %v", scanned, err)
+	}
+
+	if !scanned.Is(notFound) || scanned.Resolve(b) != notFound {
+		t.Errorf("unexpected resolved error. Ref = %s", scanned)
+	}
+
+	if err = scanned.Scan("E_OTHER"); err != nil || scanned.Namespace != "" || scanned.Code != "E_OTHER" {
+		t.Errorf("unexpected scanned string. Ref = %+v", scanned)
+	}
+
+	if scanned.Resolve(b) != b.FallbackError() {
+		t.Errorf("unexpected resolved error of other namespace")
+	}
+
+	if err = scanned.Scan(nil); err != nil || !scanned.IsZero() {
+		t.Errorf("unexpected scanned nil. Ref = %+v", scanned)
+	}
+
+	if v, _ = scanned.Value(); v != nil {
+		t.Errorf("unexpected value of empty ref. Value = %v", v)
+	}
+
+	if err = scanned.Scan(42); err == nil {
+		t.Errorf("unexpected int is scanned")
+	}
+}
@@ -0,0 +1,166 @@
+package errx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"net/url"
+	"strconv"
+	"syscall"
+)
+
+// Well-known errors of stdlib error families. Register them to a registry with RegisterStdlib
+var (
+	EOFError              = NewError("EOF", "End of file", WithExitCode(ExitDataErr))
+	UnexpectedEOFError    = NewError("UNEXPECTED_EOF", "Unexpected end of file", WithExitCode(ExitDataErr))
+	NotExistError         = NewError("NOT_EXIST", "File does not exist", WithExitCode(ExitNoInput))
+	PermissionError       = NewError("PERMISSION_DENIED", "Permission denied", WithExitCode(ExitNoPerm))
+	ExistError            = NewError("ALREADY_EXISTS", "File already exists", WithExitCode(ExitCantCreate))
+	CanceledError         = NewError("CANCELED", "Operation canceled")
+	DeadlineExceededError = NewError("DEADLINE_EXCEEDED", "Deadline exceeded", WithExitCode(ExitTempFail))
+	TimeoutError          = NewError("TIMEOUT", "Network timeout", WithExitCode(ExitTempFail))
+	ConnRefusedError      = NewError("CONNECTION_REFUSED", "Connection refused", WithExitCode(ExitUnavailable))
+	ConnResetError        = NewError("CONNECTION_RESET", "Connection reset by peer", WithExitCode(ExitUnavailable))
+	NoSpaceError          = NewError("NO_SPACE", "No space left on device", WithExitCode(ExitIOErr))
+	URLError              = NewError("URL_ERROR", "Request to URL failed", WithExitCode(ExitUnavailable))
+	InvalidNumberError    = NewError("INVALID_NUMBER", "Invalid number", WithExitCode(ExitDataErr))
+	InvalidJSONError      = NewError("INVALID_JSON", "Invalid JSON", WithExitCode(ExitDataErr))
+)
+
+// Metadata keys extracted by stdlib translators
+const (
+	MetaOp     = "op"
+	MetaPath   = "path"
+	MetaURL    = "url"
+	MetaErrno  = "errno"
+	MetaFunc   = "func"
+	MetaNum    = "num"
+	MetaOffset = "offset"
+	MetaField  = "field"
+)
+
+// RegisterStdlib registers translators of common stdlib errors to registry. Translators are registered from
+// the most specific one, e.g. context and syscall errors are evaluated before wrapping net and url errors
+func RegisterStdlib(r *Registry) {
+	r.Register(translateContext)
+	r.Register(translateErrno)
+	r.Register(translateTimeout)
+	r.Register(translateFS)
+	r.Register(translateIO)
+	r.Register(translateURL)
+	r.Register(translateParse)
+}
+
+func translateContext(err error) *Error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return CanceledError
+	case errors.Is(err, context.DeadlineExceeded):
+		return DeadlineExceededError
+	}
+	return nil
+}
+
+func translateErrno(err error) *Error {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return nil
+	}
+
+	var tErr *Error
+	switch errno {
+	case syscall.ECONNREFUSED:
+		tErr = ConnRefusedError
+	case syscall.ECONNRESET:
+		tErr = ConnResetError
+	case syscall.ENOSPC:
+		tErr = NoSpaceError
+	default:
+		return nil
+	}
+
+	return tErr.Copy(withStdlibMetadata(err, AddMetadata(MetaErrno, int(errno)))...)
+}
+
+func translateTimeout(err error) *Error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return TimeoutError.Copy(withStdlibMetadata(err)...)
+	}
+	return nil
+}
+
+func translateFS(err error) *Error {
+	var tErr *Error
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		tErr = NotExistError
+	case errors.Is(err, fs.ErrPermission):
+		tErr = PermissionError
+	case errors.Is(err, fs.ErrExist):
+		tErr = ExistError
+	default:
+		return nil
+	}
+	return tErr.Copy(withStdlibMetadata(err)...)
+}
+
+func translateIO(err error) *Error {
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return UnexpectedEOFError
+	case errors.Is(err, io.EOF):
+		return EOFError
+	}
+	return nil
+}
+
+func translateURL(err error) *Error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return URLError.Copy(withStdlibMetadata(err)...)
+	}
+	return nil
+}
+
+func translateParse(err error) *Error {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return InvalidNumberError.Copy(AddMetadata(MetaFunc, numErr.Func), AddMetadata(MetaNum, numErr.Num))
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return InvalidJSONError.Copy(AddMetadata(MetaOffset, syntaxErr.Offset))
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return InvalidJSONError.Copy(AddMetadata(MetaOffset, typeErr.Offset), AddMetadata(MetaField, typeErr.Field))
+	}
+
+	return nil
+}
+
+// withStdlibMetadata extracts op, path and url metadata from common stdlib error types in chain
+func withStdlibMetadata(err error, args ...SetOptionFn) []SetOptionFn {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		args = append(args, AddMetadata(MetaOp, pathErr.Op), AddMetadata(MetaPath, pathErr.Path))
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		args = append(args, AddMetadata(MetaOp, urlErr.Op), AddMetadata(MetaURL, urlErr.URL))
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && urlErr == nil {
+		args = append(args, AddMetadata(MetaOp, opErr.Op))
+	}
+
+	return args
+}
@@ -0,0 +1,99 @@
+package errx_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/nbs-go/errx"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+type testNetTimeoutError struct{}
+
+func (testNetTimeoutError) Error() string   { return "i/o timeout" }
+func (testNetTimeoutError) Timeout() bool   { return true }
+func (testNetTimeoutError) Temporary() bool { return true }
+
+func TestRegisterStdlib(t *testing.T) {
+	r := errx.NewRegistry()
+	errx.RegisterStdlib(r)
+
+	_, openErr := os.Open("/errx/not/exist")
+	_, numErr := strconv.Atoi("abc")
+	jsonErr := json.Unmarshal([]byte(`{"a":`), new(map[string]interface{}))
+
+	refusedErr := &url.Error{Op: "Get", URL: "http://localhost:1", Err: &net.OpError{
+		Op:  "dial",
+		Net: "tcp",
+		Err: os.NewSyscallError("connect", syscall.ECONNREFUSED),
+	}}
+
+	testCases := []struct {
+		err      error
+		expected *errx.Error
+		metadata map[string]interface{}
+	}{
+		{io.EOF, errx.EOFError, nil},
+		{fmt.Errorf("read header: %w", io.ErrUnexpectedEOF), errx.UnexpectedEOFError, nil},
+		{openErr, errx.NotExistError, map[string]interface{}{errx.MetaOp: "open", errx.MetaPath: "/errx/not/exist"}},
+		{&os.PathError{Op: "write", Path: "/etc/passwd", Err: syscall.EACCES}, errx.PermissionError,
+			map[string]interface{}{errx.MetaPath: "/etc/passwd"}},
+		{os.ErrExist, errx.ExistError, nil},
+		{context.Canceled, errx.CanceledError, nil},
+		{fmt.Errorf("query: %w", context.DeadlineExceeded), errx.DeadlineExceededError, nil},
+		{&url.Error{Op: "Get", URL: "http://example.com", Err: testNetTimeoutError{}}, errx.TimeoutError,
+			map[string]interface{}{errx.MetaURL: "http://example.com"}},
+		{refusedErr, errx.ConnRefusedError, map[string]interface{}{
+			errx.MetaErrno: int(syscall.ECONNREFUSED),
+			errx.MetaOp:    "Get",
+			errx.MetaURL:   "http://localhost:1",
+		}},
+		{&url.Error{Op: "Get", URL: "http://example.com", Err: errors.New("bad")}, errx.URLError, nil},
+		{syscall.ENOSPC, errx.NoSpaceError, map[string]interface{}{errx.MetaErrno: int(syscall.ENOSPC)}},
+		{numErr, errx.InvalidNumberError, map[string]interface{}{errx.MetaFunc: "Atoi", errx.MetaNum: "abc"}},
+		{jsonErr, errx.InvalidJSONError, map[string]interface{}{errx.MetaOffset: int64(5)}},
+	}
+
+	for i, tc := range testCases {
+		tErr, ok := r.Translate(tc.err)
+		if !ok || !errors.Is(tErr, tc.expected) {
+			t.Errorf("unexpected translated error. Index = %d, Error = %s, Translated = %v", i, tc.err, tErr)
+			continue
+		}
+
+		for k, v := range tc.metadata {
+			if actual, _ := tErr.Meta().Lookup(k); actual != v {
+				t.Errorf("unexpected metadata %s. Index = %d, Value = %v", k, i, actual)
+			}
+		}
+	}
+
+	// Template error must not be modified
+	if errx.NotExistError.Meta().Len() != 0 {
+		t.Errorf("unexpected template error metadata is modified")
+	}
+}
+
+func TestTraceStdlibError(t *testing.T) {
+	r := errx.NewRegistry()
+	errx.RegisterStdlib(r)
+	b := errx.NewBuilder("mycli", errx.WithRegistry(r))
+
+	_, openErr := os.Open("/errx/not/exist")
+	err := b.Trace(openErr)
+
+	if !errors.Is(err, errx.NotExistError) || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("unexpected traced stdlib error. Error = %s", err)
+	}
+
+	if code := errx.ExitCode(err); code != errx.ExitNoInput {
+		t.Errorf("unexpected exit code. Code = %d", code)
+	}
+}
@@ -0,0 +1,243 @@
+package errx
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceEntry is a location where error is traced, with optional note that describes why error passed through.
+// Time and Goroutine are only set if enabled by RecordTime and RecordGoroutine configuration
+type TraceEntry struct {
+	File      string
+	Line      int
+	Function  string
+	Note      string
+	Time      time.Time
+	Goroutine int64
+}
+
+// String returns trace location in "file:line" format
+func (t TraceEntry) String() string {
+	return fmt.Sprintf("%s:%d", t.File, t.Line)
+}
+
+// Equal check if both trace entries point to the same location, with the same note and time
+func (t TraceEntry) Equal(other TraceEntry) bool {
+	return t.File == other.File && t.Line == other.Line && t.Function == other.Function && t.Note == other.Note &&
+		t.Time.Equal(other.Time) && t.Goroutine == other.Goroutine
+}
+
+// sameFrame check if both trace entries point to the same location, regardless of its note and time
+func sameFrame(a, b TraceEntry) bool {
+	return a.File == b.File && a.Line == b.Line && a.Function == b.Function
+}
+
+// commonFrames count frames at the end of traces that are shared with enclosing traces
+func commonFrames(traces, enclosing []TraceEntry) int {
+	n := 0
+	for i, j := len(traces)-1, len(enclosing)-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		if !sameFrame(traces[i], enclosing[j]) {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+type traceEntryJSON struct {
+	File      string     `json:"file"`
+	Line      int        `json:"line"`
+	Function  string     `json:"function,omitempty"`
+	Note      string     `json:"note,omitempty"`
+	Time      *time.Time `json:"time,omitempty"`
+	Goroutine int64      `json:"goroutine,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler interface. Empty note, time and goroutine are omitted
+func (t TraceEntry) MarshalJSON() ([]byte, error) {
+	j := traceEntryJSON{
+		File:      t.File,
+		Line:      t.Line,
+		Function:  t.Function,
+		Note:      t.Note,
+		Goroutine: t.Goroutine,
+	}
+	if !t.Time.IsZero() {
+		j.Time = &t.Time
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface
+func (t *TraceEntry) UnmarshalJSON(data []byte) error {
+	var j traceEntryJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*t = TraceEntry{
+		File:      j.File,
+		Line:      j.Line,
+		Function:  j.Function,
+		Note:      j.Note,
+		Goroutine: j.Goroutine,
+	}
+	if j.Time != nil {
+		t.Time = *j.Time
+	}
+	return nil
+}
+
+// elapsed returns time elapsed between trace entry i and the previous one, which is the next entry in list
+// since traces are sorted from the most recent. It returns false if either entry has no timestamp
+func elapsed(traces []TraceEntry, i int) (time.Duration, bool) {
+	if i+1 >= len(traces) || traces[i].Time.IsZero() || traces[i+1].Time.IsZero() {
+		return 0, false
+	}
+	return traces[i].Time.Sub(traces[i+1].Time), true
+}
+
+// formatElapsed format duration in "+12ms" format. Duration below a millisecond is printed in microseconds
+func formatElapsed(d time.Duration) string {
+	if d < time.Millisecond && d > -time.Millisecond {
+		return "+" + d.Round(time.Microsecond).String()
+	}
+	return "+" + d.Round(time.Millisecond).String()
+}
+
+// PathFormatter formats file path of trace entry in Traces and Error output
+type PathFormatter func(t TraceEntry) string
+
+// FullPath prints file path as recorded at build time. This is the default path formatter
+func FullPath(t TraceEntry) string {
+	return t.File
+}
+
+// BaseName prints only file name, e.g. "error.go"
+func BaseName(t TraceEntry) string {
+	return path.Base(t.File)
+}
+
+// PackagePath prints file path as package import path and file name, e.g. "github.com/nbs-go/errx/error.go",
+// which trims GOPATH, module cache and module root directory. Full path is printed if function is unknown
+func PackagePath(t TraceEntry) string {
+	pkg := packagePath(t)
+	if pkg == "" {
+		return t.File
+	}
+	return pkg + "/" + path.Base(t.File)
+}
+
+// ModuleRelative prints file path relative to main module root, e.g. "internal/db/repo.go". Files outside main
+// module are printed with PackagePath
+func ModuleRelative(t TraceEntry) string {
+	p := PackagePath(t)
+	if mod := mainModule(); mod != "" && strings.HasPrefix(p, mod+"/") {
+		return p[len(mod)+1:]
+	}
+	return p
+}
+
+// packagePath resolve import path of package from trace function, e.g. "github.com/nbs-go/errx"
+// from "github.com/nbs-go/errx.(*Error).Trace"
+func packagePath(t TraceEntry) string {
+	if t.Function == "" {
+		return ""
+	}
+
+	// Dots in the last element of import path are escaped in function name, e.g. "gopkg.in/yaml%2ev3.Unmarshal",
+	// so the first dot after the last slash ends the package path
+	slash := strings.LastIndexByte(t.Function, '/')
+	end := strings.IndexByte(t.Function[slash+1:], '.')
+	if end < 0 {
+		return ""
+	}
+
+	pkg := t.Function[:slash+1+end]
+	if pkg == "main" {
+		return mainPackage()
+	}
+
+	if unescaped, err := url.PathUnescape(pkg); err == nil {
+		pkg = unescaped
+	}
+
+	// External test package lives in the same directory with the tested package
+	return strings.TrimSuffix(pkg, "_test")
+}
+
+var (
+	buildInfoOnce sync.Once
+	buildInfo     *debug.BuildInfo
+
+	mainModuleOnce sync.Once
+	mainModulePath string
+)
+
+func readBuildInfo() *debug.BuildInfo {
+	buildInfoOnce.Do(func() {
+		buildInfo, _ = debug.ReadBuildInfo()
+	})
+	return buildInfo
+}
+
+// mainModule returns main module path from build info. If it is not available, e.g. in test binaries of
+// older Go versions, module path is read from the nearest go.mod of working directory. Empty string is returned
+// if main module is unknown
+func mainModule() string {
+	mainModuleOnce.Do(func() {
+		if bi := readBuildInfo(); bi != nil && bi.Main.Path != "" {
+			mainModulePath = bi.Main.Path
+			return
+		}
+		mainModulePath = workDirModule()
+	})
+	return mainModulePath
+}
+
+// workDirModule returns module path declared in the nearest go.mod of working directory
+func workDirModule() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for {
+		if b, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+			return parseModulePath(b)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// parseModulePath returns module path of module directive in go.mod
+func parseModulePath(gomod []byte) string {
+	for _, line := range strings.Split(string(gomod), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
+
+// mainPackage returns import path of main package, or empty string if build info is not available
+func mainPackage() string {
+	if bi := readBuildInfo(); bi != nil {
+		return bi.Path
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+package errx_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/nbs-go/errx"
+	"strings"
+	"testing"
+)
+
+func TestTraceWithNote(t *testing.T) {
+	srcErr := errors.New("connection reset")
+	err := errx.InternalError().Trace(errx.Source(errx.Trace(srcErr)), errx.WithNote("while loading invoice"))
+	err = err.Trace(errx.Annotate("while handling request %d", 42))
+
+	entries := err.TraceEntries()
+	if len(entries) != 3 {
+		t.Errorf("unexpected trace entries length. Length = %d", len(entries))
+		return
+	}
+
+	expected := []string{"while handling request 42", "while loading invoice", ""}
+	for i, e := range entries {
+		if e.Note != expected[i] {
+			t.Errorf("unexpected note. Index = %d, Note = %s", i, e.Note)
+		}
+		if !strings.HasSuffix(e.File, "trace_test.go") || e.Line == 0 {
+			t.Errorf("unexpected trace location. Index = %d, Trace = %s", i, e)
+		}
+	}
+
+	msg := err.Error()
+	if !strings.Contains(msg, "Traces => github.com/nbs-go/errx/trace_test.go:14 (while handling request 42)\n") ||
+		!strings.Contains(msg, "github.com/nbs-go/errx/trace_test.go:13 (while loading invoice)\n") {
+		t.Errorf("unexpected notes in error message. Message = %s", msg)
+	}
+
+	// Traces keeps "file:line" format
+	if traces := err.Traces(); traces[0] != "github.com/nbs-go/errx/trace_test.go:14" {
+		t.Errorf("unexpected trace. Trace = %s", traces[0])
+	}
+}
+
+func TestTraceNoteJSON(t *testing.T) {
+	err := errx.InternalError().Trace(errx.WithNote("while saving invoice"))
+
+	b, jErr := json.Marshal(err)
+	if jErr != nil {
+		t.Errorf("unexpected error on marshal. Error = %s", jErr)
+		return
+	}
+
+	var decoded errx.Error
+	if jErr = json.Unmarshal(b, &decoded); jErr != nil {
+		t.Errorf("unexpected error on unmarshal. Error = %s", jErr)
+		return
+	}
+
+	entries := decoded.TraceEntries()
+	if len(entries) != 1 || entries[0] != err.TraceEntries()[0] {
+		t.Errorf("unexpected decoded trace entries. Entries = %+v", entries)
+	}
+}
+
+func TestTrimCommonFrames(t *testing.T) {
+	data := []byte(`{
+		"code": "PANIC",
+		"message": "Recovered from panic",
+		"traces": [
+			{"file": "/app/handler.go", "line": 20, "function": "app.handle"},
+			{"file": "/app/server.go", "line": 10, "function": "app.serve"},
+			{"file": "/app/main.go", "line": 5, "function": "main.main"}
+		],
+		"cause": {
+			"code": "ERR_1",
+			"message": "Bad Request",
+			"traces": [
+				{"file": "/app/repo.go", "line": 42, "function": "app.find"},
+				{"file": "/app/server.go", "line": 10, "function": "app.serve"},
+				{"file": "/app/main.go", "line": 5, "function": "main.main"}
+			]
+		}
+	}`)
+
+	var err errx.Error
+	if jErr := json.Unmarshal(data, &err); jErr != nil {
+		t.Errorf("unexpected error on unmarshal. Error = %s", jErr)
+		return
+	}
+
+	expected := "Recovered from panic" +
+		"\n  Traces => /app/handler.go:20" +
+		"\n            /app/server.go:10" +
+		"\n            /app/main.go:5" +
+		"\n  CausedBy => Bad Request" +
+		"\n  Traces => /app/repo.go:42" +
+		"\n            ... 2 more"
+	if msg := err.Render(errx.TracePath(errx.FullPath)); msg != expected {
+		t.Errorf("unexpected trimmed message. Message = %s", msg)
+	}
+
+	// Full frames are available programmatically
+	var cause *errx.Error
+	if !errors.As(err.Unwrap(), &cause) || len(cause.Traces()) != 3 {
+		t.Errorf("unexpected cause traces. Cause = %v", err.Unwrap())
+	}
+}
@@ -0,0 +1,118 @@
+package errx
+
+import (
+	"errors"
+	"sync"
+)
+
+// Translator converts a foreign error into *errx.Error. It returns nil if error is not handled
+type Translator func(err error) *Error
+
+// DefaultRegistry is consulted by Trace and Wrap to translate errors that is not *errx.Error
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry creates an empty translator registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Registry maps foreign errors to *errx.Error. Translators are evaluated in registration order,
+// the first translator that handles the error wins
+type Registry struct {
+	mu          sync.RWMutex
+	translators []*Translator
+}
+
+// Register add translator to registry. It returns function that removes the translator from registry
+func (r *Registry) Register(t Translator) (unregister func()) {
+	entry := &t
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.translators = append(r.translators, entry)
+
+	return func() {
+		r.unregister(entry)
+	}
+}
+
+// unregister removes translator. Translators are copied, so Translate that is running is not affected
+func (r *Registry) unregister(entry *Translator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	translators := make([]*Translator, 0, len(r.translators))
+	for _, t := range r.translators {
+		if t != entry {
+			translators = append(translators, t)
+		}
+	}
+	r.translators = translators
+}
+
+// RegisterIs maps errors that satisfy errors.Is(err, target) to error. It returns function that removes
+// the translator from registry
+func (r *Registry) RegisterIs(target error, to *Error) (unregister func()) {
+	return r.Register(func(err error) *Error {
+		if errors.Is(err, target) {
+			return to
+		}
+		return nil
+	})
+}
+
+// RegisterMatch maps errors that satisfy predicate to error. It returns function that removes the translator
+// from registry
+func (r *Registry) RegisterMatch(match func(error) bool, to *Error) (unregister func()) {
+	return r.Register(func(err error) *Error {
+		if match(err) {
+			return to
+		}
+		return nil
+	})
+}
+
+// RegisterType maps errors that has type T in chain, checked by errors.As, to error. It returns function that
+// removes the translator from registry
+func RegisterType[T error](r *Registry, to *Error) (unregister func()) {
+	return r.Register(func(err error) *Error {
+		var target T
+		if errors.As(err, &target) {
+			return to
+		}
+		return nil
+	})
+}
+
+// Translate returns *errx.Error that is mapped to error. It returns false if no translator handles the error
+func (r *Registry) Translate(err error) (*Error, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	r.mu.RLock()
+	translators := r.translators
+	r.mu.RUnlock()
+
+	for _, t := range translators {
+		if tErr := (*t)(err); tErr != nil {
+			return tErr, true
+		}
+	}
+
+	return nil, false
+}
+
+// translate resolves *errx.Error for err. If err is not *errx.Error and it is not handled by registry,
+// then fallback error is returned
+func translate(r *Registry, err error, fallback func() *Error) *Error {
+	if xErr, ok := err.(*Error); ok {
+		return xErr
+	}
+
+	if tErr, ok := r.Translate(err); ok {
+		return tErr
+	}
+
+	return fallback()
+}
@@ -0,0 +1,150 @@
+package errx_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nbs-go/errx"
+	"strings"
+	"testing"
+)
+
+var errTestRecordNotFound = errors.New("test: record not found")
+
+type testTimeoutError struct {
+	op string
+}
+
+func (e *testTimeoutError) Error() string {
+	return e.op + ": timeout"
+}
+
+func TestRegistryTranslate(t *testing.T) {
+	b := errx.NewBuilder("myapp")
+	notFound := b.NewError("E_NOT_FOUND", "Resource not found")
+	timeout := b.NewError("E_TIMEOUT", "Request timeout")
+	unavailable := b.NewError("E_UNAVAILABLE", "Service unavailable")
+
+	r := errx.NewRegistry()
+	r.RegisterIs(errTestRecordNotFound, notFound)
+	errx.RegisterType[*testTimeoutError](r, timeout)
+	r.RegisterMatch(func(err error) bool {
+		return strings.Contains(err.Error(), "connection refused")
+	}, unavailable)
+
+	testCases := []struct {
+		err      error
+		expected *errx.Error
+	}{
+		{fmt.Errorf("load invoice: %w", errTestRecordNotFound), notFound},
+		{fmt.Errorf("call api: %w", &testTimeoutError{op: "GET"}), timeout},
+		{fmt.Errorf("dial tcp: connection refused"), unavailable},
+	}
+
+	for _, tc := range testCases {
+		tErr, ok := r.Translate(tc.err)
+		if !ok || tErr != tc.expected {
+			t.Errorf("unexpected translated error. Error = %s, Translated = %v", tc.err, tErr)
+		}
+	}
+
+	if _, ok := r.Translate(fmt.Errorf("unknown")); ok {
+		t.Errorf("unexpected unknown error is translated")
+	}
+
+	if _, ok := r.Translate(nil); ok {
+		t.Errorf("unexpected nil error is translated")
+	}
+}
+
+func TestTraceWithDefaultRegistry(t *testing.T) {
+	notFound := errx.NewError("E_NOT_FOUND", "Resource not found", errx.WithNamespace("myapp"))
+	defer errx.DefaultRegistry.RegisterIs(errTestRecordNotFound, notFound)()
+
+	srcErr := fmt.Errorf("load invoice: %w", errTestRecordNotFound)
+	err := errx.Trace(srcErr)
+
+	if !errors.Is(err, notFound) {
+		t.Errorf("unexpected traced error is not translated. Error = %s", err)
+	}
+
+	if errors.Unwrap(err) != srcErr {
+		t.Errorf("unexpected traced error does not keep original as source. Error = %s", err)
+	}
+
+	if xErr := err.(*errx.Error); len(xErr.Traces()) != 1 || !strings.HasSuffix(xErr.Traces()[0], "translate_test.go:64") {
+		t.Errorf("unexpected traces. Traces = %+v", xErr.Traces())
+	}
+
+	wErr := errx.Wrap(srcErr)
+	if !errors.Is(wErr, notFound) || !errors.Is(wErr, errTestRecordNotFound) {
+		t.Errorf("unexpected wrapped error is not translated. Error = %s", wErr)
+	}
+
+	// Unknown error is still wrapped as InternalError
+	if err = errx.Trace(fmt.Errorf("unknown")); !errors.Is(err, errx.InternalError()) {
+		t.Errorf("unexpected unknown error is translated. Error = %s", err)
+	}
+}
+
+func TestBuilderRegistry(t *testing.T) {
+	r := errx.NewRegistry()
+	b := errx.NewBuilder("myapp", errx.WithRegistry(r))
+	timeout := b.NewError("E_TIMEOUT", "Request timeout")
+	errx.RegisterType[*testTimeoutError](r, timeout)
+
+	if b.Registry() != r {
+		t.Errorf("unexpected builder registry")
+	}
+
+	err := b.Trace(&testTimeoutError{op: "GET"})
+	if !errors.Is(err, timeout) {
+		t.Errorf("unexpected traced error is not translated. Error = %s", err)
+	}
+
+	// Not handled error is wrapped into builder fallback error
+	err = b.Trace(fmt.Errorf("unknown"))
+	if !errors.Is(err, b.FallbackError()) {
+		t.Errorf("unexpected traced error is not fallback error. Error = %s", err)
+	}
+
+	wErr := b.Wrap(fmt.Errorf("unknown"))
+	if !errors.Is(wErr, b.FallbackError()) || wErr.Unwrap().Error() != "unknown" {
+		t.Errorf("unexpected wrapped error. Error = %s", wErr)
+	}
+
+	// errx.Error is traced as is
+	if err = b.Trace(timeout); !errors.Is(err, timeout) || len(err.(*errx.Error).Traces()) != 1 {
+		t.Errorf("unexpected traced errx.Error. Error = %s", err)
+	}
+
+	if b.Trace(nil) != nil || b.Wrap(nil) != nil {
+		t.Errorf("unexpected nil error is traced")
+	}
+
+	// Builder without registry uses DefaultRegistry
+	if errx.NewBuilder("otherapp").Registry() != errx.DefaultRegistry {
+		t.Errorf("unexpected default builder registry")
+	}
+}
+
+func TestRegistryUnregister(t *testing.T) {
+	r := errx.NewRegistry()
+	notFound := errx.NewError("E_NOT_FOUND", "Resource not found")
+	unavailable := errx.NewError("E_UNAVAILABLE", "Service unavailable")
+
+	unregister := r.RegisterIs(errTestRecordNotFound, notFound)
+	r.RegisterMatch(func(error) bool {
+		return true
+	}, unavailable)
+
+	if tErr, _ := r.Translate(errTestRecordNotFound); tErr != notFound {
+		t.Errorf("unexpected translated error. Translated = %v", tErr)
+	}
+
+	unregister()
+	unregister()
+
+	if tErr, _ := r.Translate(errTestRecordNotFound); tErr != unavailable {
+		t.Errorf("unexpected unregistered translator is evaluated. Translated = %v", tErr)
+	}
+}
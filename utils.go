@@ -1,53 +1,101 @@
 package errx
 
 import (
-	"fmt"
 	"runtime"
 )
 
 func InternalError() *Error {
-	return NewError("ERROR", "Internal Error")
+	return newError("ERROR", "Internal Error")
 }
 
-// Trace wrap and trace error. If error is not *errx.Error then it will be wrapped into InternalError
-// Else, it will add stack trace to error
+func PanicError() *Error {
+	return newError("PANIC", "Recovered from panic")
+}
+
+// Trace wrap and trace error. If error is not *errx.Error then it will be translated by DefaultRegistry,
+// or wrapped into InternalError if no translator handles it. Else, it will add stack trace to error
 func Trace(err error) error {
 	if err == nil {
 		return nil
 	}
 
-	// Check error type
-	tErr, ok := err.(*Error)
-	if !ok {
-		// Set as internal error
-		tErr = InternalError()
-	}
+	tErr := translate(DefaultRegistry, err, InternalError)
 
 	return tErr.Trace(Source(err), SkipTrace(2))
 }
 
+// Wrap set err as source of error translated by DefaultRegistry, or InternalError if no translator handles it
 func Wrap(err error) *Error {
-	return InternalError().Wrap(err)
+	if err == nil {
+		return nil
+	}
+
+	tErr, ok := DefaultRegistry.Translate(err)
+	if !ok {
+		tErr = InternalError()
+	}
+
+	nErr := tErr.wrap(err)
+	wrapHooks.fire(nErr, 1)
+	return nErr
 }
 
 // trace returns where in file and line the function being called
-func trace(skip int) string {
-	_, file, line, _ := runtime.Caller(skip + 1)
-	return fmt.Sprintf("%s:%d", file, line)
+func trace(skip int) TraceEntry {
+	var pcs [1]uintptr
+	runtime.Callers(skip+2, pcs[:])
+	f, _ := runtime.CallersFrames(pcs[:]).Next()
+
+	t := TraceEntry{File: f.File, Line: f.Line, Function: f.Function}
+	stamp(loadConfig(), &t)
+	return t
 }
 
+// copyMetadata deep copy metadata, nested maps and slices are copied recursively
 func copyMetadata(m1 map[string]interface{}) map[string]interface{} {
-	m2 := make(map[string]interface{})
+	m2 := make(map[string]interface{}, len(m1))
 	for k, v := range m1 {
-		m2[k] = v
+		m2[k] = deepCopyValue(v)
 	}
 	return m2
 }
 
-func copyTraces(m1 []string) []string {
-	m2 := make([]string, len(m1))
+func copyTraces(m1 []TraceEntry) []TraceEntry {
+	m2 := make([]TraceEntry, len(m1))
 	for k, v := range m1 {
 		m2[k] = v
 	}
 	return m2
 }
+
+// walk traverses error chain in depth-first order, including errors aggregated in Errors or with Unwrap() []error,
+// until fn returns true
+func walk(err error, fn func(error) bool) bool {
+	for err != nil {
+		if fn(err) {
+			return true
+		}
+
+		switch u := err.(type) {
+		case Errors:
+			for _, e := range u {
+				if walk(e, fn) {
+					return true
+				}
+			}
+			return false
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				if walk(e, fn) {
+					return true
+				}
+			}
+			return false
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			return false
+		}
+	}
+	return false
+}